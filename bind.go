@@ -0,0 +1,70 @@
+package summer
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+)
+
+// A field set by a function of the container, see Bind
+type binding struct {
+	field    string
+	provider func(c *Container) interface{}
+}
+
+// Sets target's field to the value returned by provider whenever target is
+// injected into, whether by InjectInto or PerformInjections. This sidesteps
+// tags entirely for values that need computing. Bound fields are set after
+// any tagged fields, and the value must be assignable to the field, as for
+// injection by name.
+//
+// An error is returned if target isn't a non-nil pointer to a struct, or if
+// it has no exported field with the given name.
+func (c *Container) Bind(target interface{}, fieldName string, provider func(c *Container) interface{}) error {
+	if err := c.checkFrozen(); err != nil {
+		return err
+	}
+	if !isPointerToStruct(target) {
+		return fmt.Errorf("Summer: Cannot bind a field of something other than a pointer-to-struct: %w",
+			ErrNotAStruct)
+	}
+	if reflect.ValueOf(target).IsNil() {
+		return fmt.Errorf("Summer: Cannot bind a field of a nil %T: %w", target, ErrNotAStruct)
+	}
+
+	field := reflect.ValueOf(target).Elem().FieldByName(fieldName)
+	if !field.IsValid() || !field.CanSet() {
+		return errors.New(
+			fmt.Sprintf("Summer: Cannot bind %s's field %s, which doesn't exist or isn't exported",
+				reflect.TypeOf(target).Elem(), fieldName))
+	}
+
+	c.bindings[target] = append(c.bindings[target], binding{field: fieldName, provider: provider})
+	return nil
+}
+
+// Sets each of target's bound fields
+func (c *Container) applyBindings(target interface{}) error {
+	element := reflect.ValueOf(target).Elem()
+	for _, b := range c.bindings[target] {
+		typeField, _ := element.Type().FieldByName(b.field)
+		p := injectionPoint{
+			elementType: element.Type(),
+			field:       element.FieldByIndex(typeField.Index),
+			typeField:   typeField,
+			tag:         &fieldTag{},
+			target:      target,
+		}
+
+		dependency := b.provider(c)
+		value, ok := adaptDependency(dependency, typeField.Type)
+		if !ok {
+			return newTypeMismatchError(p, dependency)
+		}
+		if err := c.setField(p, value); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,71 @@
+package summer
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestBindSetsComputedValue(t *testing.T) {
+	type boundStruct struct {
+		Address string
+		Port    int `summer:"port"`
+	}
+
+	container := NewContainer()
+	container.Add(8080, "port")
+	container.Add("localhost", "host")
+	s := new(boundStruct)
+	container.Add(s, "")
+	err := container.Bind(s, "Address", func(c *Container) interface{} {
+		host, _ := c.Get("host")
+		port, _ := c.Get("port")
+		return fmt.Sprintf("%s:%d", host, port)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = container.PerformInjections()
+
+	if err != nil || s.Address != "localhost:8080" || s.Port != 8080 {
+		t.Log(err, s)
+		t.Fail()
+	}
+}
+
+func TestBindRejectsUnknownField(t *testing.T) {
+	type boundStruct struct {
+		Address string
+		port    int
+	}
+
+	container := NewContainer()
+	s := new(boundStruct)
+	missingErr := container.Bind(s, "Missing", func(c *Container) interface{} { return "" })
+	unexportedErr := container.Bind(s, "port", func(c *Container) interface{} { return 1 })
+	valueErr := container.Bind(boundStruct{}, "Address", func(c *Container) interface{} { return "" })
+	nilErr := container.Bind((*boundStruct)(nil), "Address", func(c *Container) interface{} { return "" })
+
+	if missingErr == nil || unexportedErr == nil || !errors.Is(valueErr, ErrNotAStruct) ||
+		!errors.Is(nilErr, ErrNotAStruct) {
+		t.Log(missingErr, unexportedErr, valueErr, nilErr)
+		t.Fail()
+	}
+}
+
+func TestBindRejectsMismatchedValue(t *testing.T) {
+	type boundStruct struct {
+		Address string
+	}
+
+	container := NewContainer()
+	s := new(boundStruct)
+	container.Bind(s, "Address", func(c *Container) interface{} { return 1 })
+	err := container.InjectInto(s)
+
+	var mismatch *TypeMismatchError
+	if !errors.As(err, &mismatch) {
+		t.Log(err)
+		t.Fail()
+	}
+}
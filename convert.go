@@ -0,0 +1,130 @@
+package summer
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"time"
+)
+
+var durationType = reflect.TypeOf(time.Duration(0))
+
+// Returned when a dependency can't be converted to the type of the field it
+// was meant for.
+type TypeMismatchError struct {
+	Target         reflect.Type // The struct being injected into
+	Field          string       // The name of the field being injected
+	FieldType      reflect.Type
+	DependencyType reflect.Type // Nil if the dependency was nil
+}
+
+func (e *TypeMismatchError) Error() string {
+	return fmt.Sprintf("Summer: Cannot inject dependency of type %v into %s's field %s of type %s",
+		e.DependencyType, e.Target, e.Field, e.FieldType)
+}
+
+func newTypeMismatchError(p injectionPoint, dependency interface{}) error {
+	return &TypeMismatchError{
+		Target:         p.elementType,
+		Field:          p.typeField.Name,
+		FieldType:      p.typeField.Type,
+		DependencyType: reflect.TypeOf(dependency),
+	}
+}
+
+// Converts a dependency into a value that can be set into a field of the
+// given type. Besides plain assignment, two conversions are supported:
+//
+//   - a pointer is dereferenced for a field of the pointed-to type, and
+//   - a value is copied into a newly allocated pointer for a field of
+//     pointer type.
+//
+// Both conversions copy the dependency, so the field won't observe later
+// changes made to it. A nil dependency is only allowed for fields that can
+// hold nil.
+func adaptDependency(dependency interface{}, fieldType reflect.Type) (reflect.Value, bool) {
+	value := reflect.ValueOf(dependency)
+
+	switch {
+	case !value.IsValid():
+		if isNillable(fieldType) {
+			return reflect.Zero(fieldType), true
+		}
+	case value.Type().AssignableTo(fieldType):
+		return value, true
+	case value.Kind() == reflect.Ptr && !value.IsNil() && value.Elem().Type().AssignableTo(fieldType):
+		return value.Elem(), true
+	case fieldType.Kind() == reflect.Ptr && value.Type().AssignableTo(fieldType.Elem()):
+		pointer := reflect.New(fieldType.Elem())
+		pointer.Elem().Set(value)
+		return pointer, true
+	}
+
+	return reflect.Value{}, false
+}
+
+// Whether a value of the type can be nil
+func isNillable(t reflect.Type) bool {
+	switch t.Kind() {
+	case reflect.Ptr, reflect.Interface, reflect.Map, reflect.Slice, reflect.Chan, reflect.Func:
+		return true
+	}
+	return false
+}
+
+// Returned when a string dependency can't be parsed into the primitive type
+// of the field it was meant for, see Container.ConvertPrimitives.
+type ConversionError struct {
+	Target    reflect.Type // The struct being injected into
+	Field     string       // The name of the field being injected
+	FieldType reflect.Type
+	Value     string // The string that couldn't be parsed
+	Err       error  // The underlying parse error
+}
+
+func (e *ConversionError) Error() string {
+	return fmt.Sprintf("Summer: Cannot convert %q for %s's field %s of type %s: %v",
+		e.Value, e.Target, e.Field, e.FieldType, e.Err)
+}
+
+func (e *ConversionError) Unwrap() error {
+	return e.Err
+}
+
+// Parses a string into a time.Duration, bool, number or string-kinded type.
+// False is returned for any other type.
+func convertPrimitive(s string, t reflect.Type) (reflect.Value, bool, error) {
+	value := reflect.New(t).Elem()
+
+	if t == durationType {
+		d, err := time.ParseDuration(s)
+		value.SetInt(int64(d))
+		return value, true, err
+	}
+
+	var err error
+	switch t.Kind() {
+	case reflect.String:
+		value.SetString(s)
+	case reflect.Bool:
+		var b bool
+		b, err = strconv.ParseBool(s)
+		value.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		var i int64
+		i, err = strconv.ParseInt(s, 0, t.Bits())
+		value.SetInt(i)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		var u uint64
+		u, err = strconv.ParseUint(s, 0, t.Bits())
+		value.SetUint(u)
+	case reflect.Float32, reflect.Float64:
+		var f float64
+		f, err = strconv.ParseFloat(s, t.Bits())
+		value.SetFloat(f)
+	default:
+		return reflect.Value{}, false, nil
+	}
+
+	return value, true, err
+}
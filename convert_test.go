@@ -0,0 +1,267 @@
+package summer
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"reflect"
+	"testing"
+	"time"
+)
+
+type convertedConfig struct {
+	Port int
+}
+
+func TestInjectsNamedValueIntoPointer(t *testing.T) {
+	type simpleStruct struct {
+		Config *convertedConfig `summer:"Config"`
+	}
+
+	container := NewContainer()
+	container.Add(convertedConfig{Port: 80}, "Config")
+	s := new(simpleStruct)
+	err := container.InjectInto(s)
+
+	if err != nil || s.Config == nil || s.Config.Port != 80 {
+		t.Log(err)
+		t.Fail()
+	}
+}
+
+func TestInjectsPointerToInterface(t *testing.T) {
+	type simpleStruct struct {
+		Named *io.Writer `summer:"out"`
+		Auto  *io.Writer `summer:",auto"`
+	}
+	out := new(bytes.Buffer)
+
+	container := NewContainer()
+	AddAs[io.Writer](container, out, "out")
+	s := new(simpleStruct)
+	err := container.InjectInto(s)
+
+	if err != nil || s.Named == nil || *s.Named != out || s.Auto == nil || *s.Auto != out {
+		t.Log(err)
+		t.Fail()
+	}
+}
+
+func TestInjectsNamedPointerIntoValue(t *testing.T) {
+	type simpleStruct struct {
+		Config convertedConfig `summer:"Config"`
+	}
+	config := &convertedConfig{Port: 80}
+
+	container := NewContainer()
+	container.Add(config, "Config")
+	s := new(simpleStruct)
+	err := container.InjectInto(s)
+
+	config.Port = 8080
+	if err != nil || s.Config.Port != 80 {
+		t.Log(err)
+		t.Fail()
+	}
+}
+
+func TestThrowsTypeMismatchErrorForNamedInjection(t *testing.T) {
+	type simpleStruct struct {
+		Config *convertedConfig `summer:"Config"`
+	}
+
+	container := NewContainer()
+	container.Add("not a config", "Config")
+	err := container.InjectInto(new(simpleStruct))
+
+	var mismatch *TypeMismatchError
+	if !errors.As(err, &mismatch) || mismatch.Field != "Config" {
+		t.Log(err)
+		t.Fail()
+	}
+}
+
+func TestAutoInjectsPointerRegisteredIntoValue(t *testing.T) {
+	type simpleStruct struct {
+		Config convertedConfig `summer:",auto"`
+	}
+	config := &convertedConfig{Port: 80}
+
+	container := NewContainer()
+	container.Add(config, "")
+	s := new(simpleStruct)
+	err := container.InjectInto(s)
+
+	s.Config.Port = 8080
+	if err != nil || config.Port != 80 {
+		t.Log(err)
+		t.Fail()
+	}
+}
+
+func TestAutoInjectsValueRegisteredIntoPointer(t *testing.T) {
+	type simpleStruct struct {
+		Config *convertedConfig `summer:",auto"`
+	}
+	config := convertedConfig{Port: 80}
+
+	container := NewContainer()
+	container.Add(config, "")
+	s := new(simpleStruct)
+	err := container.InjectInto(s)
+
+	if err != nil || s.Config == nil || s.Config.Port != 80 {
+		t.Log(err)
+		t.Fail()
+	}
+}
+
+func TestConvertsPrimitivesFromStrings(t *testing.T) {
+	type simpleStruct struct {
+		Timeout time.Duration `summer:"Timeout"`
+		Retries int           `summer:"Retries"`
+		Verbose bool          `summer:"Verbose"`
+		Ratio   float64       `summer:"Ratio"`
+	}
+
+	container := NewContainer()
+	container.ConvertPrimitives = true
+	container.Add("30s", "Timeout")
+	container.Add("3", "Retries")
+	container.Add("true", "Verbose")
+	container.Add("0.5", "Ratio")
+	s := new(simpleStruct)
+	err := container.InjectInto(s)
+
+	if err != nil || s.Timeout != 30*time.Second || s.Retries != 3 || !s.Verbose || s.Ratio != 0.5 {
+		t.Log(err)
+		t.Fail()
+	}
+}
+
+func TestThrowsConversionErrorForUnparsableString(t *testing.T) {
+	type simpleStruct struct {
+		Retries int `summer:"Retries"`
+	}
+
+	container := NewContainer()
+	container.ConvertPrimitives = true
+	container.Add("three", "Retries")
+	err := container.InjectInto(new(simpleStruct))
+
+	var conversion *ConversionError
+	if !errors.As(err, &conversion) || conversion.Value != "three" {
+		t.Log(err)
+		t.Fail()
+	}
+}
+
+func TestDoesNotConvertPrimitivesByDefault(t *testing.T) {
+	type simpleStruct struct {
+		Retries int `summer:"Retries"`
+	}
+
+	container := NewContainer()
+	container.Add("3", "Retries")
+	err := container.InjectInto(new(simpleStruct))
+
+	var mismatch *TypeMismatchError
+	if !errors.As(err, &mismatch) {
+		t.Log(err)
+		t.Fail()
+	}
+}
+
+type userID string
+type userName = string
+
+func TestAutoInjectsTypeAliasWithoutMatching(t *testing.T) {
+	type simpleStruct struct {
+		Name userName `summer:",auto"`
+	}
+
+	container := NewContainer()
+	container.Add("gopher", "")
+	s := new(simpleStruct)
+	err := container.InjectInto(s)
+
+	if err != nil || s.Name != "gopher" {
+		t.Log(err)
+		t.Fail()
+	}
+}
+
+func TestMatchByUnderlyingType(t *testing.T) {
+	type simpleStruct struct {
+		ID      userID `summer:",auto"`
+		NamedID userID `summer:"id"`
+		Plain   string `summer:",auto"`
+	}
+
+	container := NewContainer()
+	container.MatchByUnderlyingType = true
+	container.Add("42", "id")
+	container.Add(userID("7"), "")
+	s := new(simpleStruct)
+	err := container.InjectInto(s)
+
+	if err != nil || s.ID != "7" || s.NamedID != "42" || s.Plain != "42" {
+		t.Log(err, s)
+		t.Fail()
+	}
+}
+
+func TestDefinedTypesDontMatchByDefault(t *testing.T) {
+	type simpleStruct struct {
+		ID userID `summer:",auto"`
+	}
+
+	container := NewContainer()
+	container.Add("42", "")
+	err := container.InjectInto(new(simpleStruct))
+
+	if !errors.Is(err, ErrMissingDependency) {
+		t.Log(err)
+		t.Fail()
+	}
+}
+
+func TestTypeMatcherAllowsAssignableTypes(t *testing.T) {
+	type simpleStruct struct {
+		Handler testHandler `summer:",auto"`
+	}
+
+	container := NewContainer()
+	container.Add(namedHandler("a"), "")
+	container.Add(upperHandler("b"), "")
+	container.Add("not a handler", "")
+	container.SetTypeMatcher(func(field, candidate reflect.Type) bool {
+		return candidate.AssignableTo(field)
+	})
+	s := new(simpleStruct)
+	err := container.InjectInto(s)
+
+	if err != nil || s.Handler != upperHandler("b") {
+		t.Log(err)
+		t.Fail()
+	}
+}
+
+func TestTypeMatcherMismatchIsError(t *testing.T) {
+	type simpleStruct struct {
+		Port int `summer:",auto"`
+	}
+
+	container := NewContainer()
+	container.Add("8080", "")
+	container.SetTypeMatcher(func(field, candidate reflect.Type) bool {
+		return true
+	})
+	err := container.InjectInto(new(simpleStruct))
+
+	var mismatch *TypeMismatchError
+	if !errors.As(err, &mismatch) {
+		t.Log(err)
+		t.Fail()
+	}
+}
@@ -2,23 +2,51 @@ package summer
 
 // Type used for a set of dependencies pending injection, used
 // to avoid circular dependency problems
-type interfaceSet struct {
-	set map[interface{}]bool
-}
+type interfaceSet = set[interface{}]
 
 func newInterfaceSet() *interfaceSet {
-	return &interfaceSet{set: make(map[interface{}]bool)}
+	return newSet[interface{}]()
+}
+
+// A set of comparable values
+type set[T comparable] struct {
+	elements map[T]struct{}
+}
+
+func newSet[T comparable]() *set[T] {
+	return &set[T]{elements: make(map[T]struct{})}
 }
 
 // Returns false if the item was already part of the set, otherwise true
-func (s *interfaceSet) Add(target interface{}) bool {
-	_, present := s.set[target]
-	s.set[target] = true
+func (s *set[T]) Add(target T) bool {
+	_, present := s.elements[target]
+	s.elements[target] = struct{}{}
 	return !present
 }
 
-func (s *interfaceSet) EachElement(callback func(key interface{})) {
-	for key, _ := range s.set {
+func (s *set[T]) Contains(target T) bool {
+	_, present := s.elements[target]
+	return present
+}
+
+// Returns false if the item wasn't part of the set, otherwise true
+func (s *set[T]) Remove(target T) bool {
+	_, present := s.elements[target]
+	delete(s.elements, target)
+	return present
+}
+
+func (s *set[T]) EachElement(callback func(key T)) {
+	for key := range s.elements {
 		callback(key)
 	}
 }
+
+// Returns a new set holding the same elements
+func (s *set[T]) Copy() *set[T] {
+	copied := newSet[T]()
+	for key := range s.elements {
+		copied.elements[key] = struct{}{}
+	}
+	return copied
+}
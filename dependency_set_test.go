@@ -0,0 +1,35 @@
+package summer
+
+import (
+	"testing"
+)
+
+func TestSetAddRemove(t *testing.T) {
+	s := newSet[*hookTestingStruct]()
+	a, b := new(hookTestingStruct), new(hookTestingStruct)
+
+	addedA, addedAgain := s.Add(a), s.Add(a)
+	removedB := s.Remove(b)
+	copied := s.Copy()
+	removedA := s.Remove(a)
+
+	if !addedA || addedAgain || removedB || !removedA || s.Contains(a) || !copied.Contains(a) {
+		t.Fail()
+	}
+}
+
+func TestInterfaceSetEachElement(t *testing.T) {
+	s := newInterfaceSet()
+	s.Add("a")
+	s.Add(1)
+	s.Add("a")
+
+	count := 0
+	s.EachElement(func(key interface{}) {
+		count++
+	})
+
+	if count != 2 || !s.Contains(1) || s.Contains("b") {
+		t.Fail()
+	}
+}
@@ -0,0 +1,113 @@
+package summer
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"reflect"
+)
+
+// Names the environment variable a field of a config struct is filled
+// from, see AddEnvConfig
+const envTag = "env"
+
+// Adds one of several implementations under name, chosen by the value of
+// the environment variable envKey. For example, with APP_ENV set to "test",
+//
+//	container.AddFromEnv("Mailer", "APP_ENV", map[string]interface{}{
+//		"prod": smtpMailer,
+//		"test": fakeMailer,
+//	})
+//
+// adds fakeMailer as if by AddE.
+//
+// An error is returned if no implementation matches the variable's value,
+// including when the variable is unset and there's no "" implementation.
+func (c *Container) AddFromEnv(name string, envKey string, implementations map[string]interface{}) error {
+	value := os.Getenv(envKey)
+	implementation, ok := implementations[value]
+	if !ok {
+		return errors.New(
+			fmt.Sprintf("Summer: No implementation of %s for %s=%q", name, envKey, value))
+	}
+
+	return c.AddE(implementation, name)
+}
+
+// Fills the exported fields of target, a pointer to a config struct, from
+// the environment variables named prefix followed by each field's name as
+// written, or by the name given in the field's env tag instead, then adds
+// target by type and under each of names as if by AddWithNames, so it can
+// be auto injected. Fields of string, bool, numeric and time.Duration types
+// are supported, and are left as they are when their variable is unset. For
+// example, with APP_Port=8080 and APP_LOG_LEVEL=debug,
+//
+//	type config struct {
+//		Port     int
+//		LogLevel string `env:"LOG_LEVEL"`
+//	}
+//	container.AddEnvConfig("APP_", &config{Port: 80}, "config")
+//
+// adds a config with Port set to 8080 and LogLevel set to "debug", both by
+// type and as "config".
+//
+// An error is returned if target isn't a pointer to a struct, if a variable
+// is set for a field of an unsupported type, if a variable can't be parsed,
+// in which case it's a ConversionError, or if AddE would fail to add it. On
+// error, target may have been partly filled but isn't added.
+func (c *Container) AddEnvConfig(prefix string, target interface{}, names ...string) error {
+	if !isPointerToStruct(target) {
+		return fmt.Errorf("Summer: Cannot fill %T from the environment, it isn't a pointer-to-struct: %w",
+			target, ErrNotAStruct)
+	}
+
+	element := reflect.ValueOf(target).Elem()
+	for index := 0; index < element.NumField(); index++ {
+		field := element.Type().Field(index)
+		key := prefix + field.Name
+		if name, tagged := field.Tag.Lookup(envTag); tagged && name != "" {
+			key = prefix + name
+		}
+		s, ok := os.LookupEnv(key)
+		if !ok || !field.IsExported() {
+			continue
+		}
+
+		value, supported, err := convertPrimitive(s, field.Type)
+		if !supported {
+			return errors.New(
+				fmt.Sprintf("Summer: Cannot fill %s's field %s of type %s from %s",
+					element.Type(), field.Name, field.Type, key))
+		}
+		if err != nil {
+			return &ConversionError{
+				Target:    element.Type(),
+				Field:     field.Name,
+				FieldType: field.Type,
+				Value:     s,
+				Err:       err,
+			}
+		}
+		element.Field(index).Set(value)
+	}
+
+	return c.addWithNames(target, names)
+}
+
+// Expands environment variable references in s, see Container.ExpandEnv
+func (c *Container) expandEnv(s string) (string, error) {
+	var unset []string
+	expanded := os.Expand(s, func(key string) string {
+		value, ok := os.LookupEnv(key)
+		if !ok {
+			unset = append(unset, key)
+		}
+		return value
+	})
+
+	if c.StrictEnv && len(unset) > 0 {
+		return "", errors.New(fmt.Sprintf("environment variable %s is not set", unset[0]))
+	}
+
+	return expanded, nil
+}
@@ -0,0 +1,132 @@
+package summer
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestAddIf(t *testing.T) {
+	container := NewContainer()
+	container.AddIf(true, "added", "Added")
+	container.AddIf(false, "skipped", "Skipped")
+
+	_, hasAdded := container.Get("Added")
+	_, hasSkipped := container.Get("Skipped")
+	if !hasAdded || hasSkipped {
+		t.Fail()
+	}
+}
+
+func TestAddFromEnv(t *testing.T) {
+	t.Setenv("SUMMER_TEST_ENV", "test")
+	implementations := map[string]interface{}{
+		"prod": "smtp mailer",
+		"test": "fake mailer",
+	}
+
+	container := NewContainer()
+	err := container.AddFromEnv("Mailer", "SUMMER_TEST_ENV", implementations)
+
+	mailer, _ := container.Get("Mailer")
+	if err != nil || mailer != "fake mailer" {
+		t.Log(err)
+		t.Fail()
+	}
+}
+
+func TestAddFromEnvWithoutMatchingImplementation(t *testing.T) {
+	t.Setenv("SUMMER_TEST_ENV", "staging")
+
+	container := NewContainer()
+	err := container.AddFromEnv("Mailer", "SUMMER_TEST_ENV", map[string]interface{}{
+		"prod": "smtp mailer",
+	})
+
+	if _, ok := container.Get("Mailer"); err == nil || ok {
+		t.Fail()
+	}
+}
+
+func TestExpandsEnvInNamedStrings(t *testing.T) {
+	type simpleStruct struct {
+		Password string `summer:"Password"`
+		Missing  string `summer:"Missing"`
+	}
+	t.Setenv("SUMMER_TEST_SECRET", "hunter2")
+
+	container := NewContainer()
+	container.ExpandEnv = true
+	container.Add("pass:${SUMMER_TEST_SECRET}", "Password")
+	container.Add("[${SUMMER_TEST_UNSET}]", "Missing")
+	s := new(simpleStruct)
+	err := container.InjectInto(s)
+
+	if err != nil || s.Password != "pass:hunter2" || s.Missing != "[]" {
+		t.Log(err, s)
+		t.Fail()
+	}
+}
+
+func TestStrictEnvRejectsUnsetVariables(t *testing.T) {
+	type simpleStruct struct {
+		Missing string `summer:"Missing"`
+	}
+
+	container := NewContainer()
+	container.ExpandEnv = true
+	container.StrictEnv = true
+	container.Add("${SUMMER_TEST_UNSET}", "Missing")
+	err := container.InjectInto(new(simpleStruct))
+
+	if err == nil || !strings.Contains(err.Error(), "SUMMER_TEST_UNSET") {
+		t.Log(err)
+		t.Fail()
+	}
+}
+
+type envConfig struct {
+	Host     string
+	Port     int
+	Debug    bool
+	Timeout  time.Duration
+	LogLevel string `env:"LOG_LEVEL"`
+}
+
+func TestAddEnvConfig(t *testing.T) {
+	type simpleStruct struct {
+		Config *envConfig `summer:",auto"`
+		Named  *envConfig `summer:"config"`
+	}
+	t.Setenv("APP_Host", "example.com")
+	t.Setenv("APP_Port", "8080")
+	t.Setenv("APP_Debug", "true")
+	t.Setenv("APP_LOG_LEVEL", "debug")
+	t.Setenv("APP_LogLevel", "ignored")
+
+	container := NewContainer()
+	config := &envConfig{Host: "localhost", Timeout: time.Second}
+	err := container.AddEnvConfig("APP_", config, "config")
+	s := new(simpleStruct)
+	injectErr := container.InjectInto(s)
+
+	expected := envConfig{Host: "example.com", Port: 8080, Debug: true, Timeout: time.Second, LogLevel: "debug"}
+	if err != nil || injectErr != nil || s.Config != config || s.Named != config || *config != expected {
+		t.Log(err, injectErr, config)
+		t.Fail()
+	}
+}
+
+func TestAddEnvConfigReportsConversionError(t *testing.T) {
+	t.Setenv("APP_Port", "eighty")
+
+	container := NewContainer()
+	err := container.AddEnvConfig("APP_", new(envConfig))
+
+	var conversionErr *ConversionError
+	if !errors.As(err, &conversionErr) || conversionErr.Field != "Port" || container.Len() != 0 {
+		t.Log(err)
+		t.Fail()
+	}
+}
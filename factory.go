@@ -0,0 +1,445 @@
+package summer
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+)
+
+var errorType = reflect.TypeOf((*error)(nil)).Elem()
+
+// Kinds of factory, also used to describe them in error messages
+const (
+	kindFactory   = "factory"
+	kindProvider  = "provider"
+	kindPrototype = "prototype"
+	kindScoped    = "scoped provider"
+)
+
+// A function that constructs a dependency on demand. Factories take no
+// arguments, while providers have theirs resolved by type from the container.
+// Prototypes are providers that run every time their result is injected.
+type factory struct {
+	fn   reflect.Value
+	kind string // One of the kind constants
+	name string // May be blank if the result is only injectable by type
+}
+
+// Wraps fn, which must return T or (T, error). Only providers and prototypes
+// may take arguments.
+func newFactory(fn interface{}, kind string, name string) (*factory, error) {
+	fnType := reflect.TypeOf(fn)
+	if fnType == nil || fnType.Kind() != reflect.Func || fnType.IsVariadic() ||
+		(kind == kindFactory && fnType.NumIn() != 0) ||
+		fnType.NumOut() < 1 || fnType.NumOut() > 2 ||
+		(fnType.NumOut() == 2 && fnType.Out(1) != errorType) {
+		return nil, errors.New(
+			fmt.Sprintf("Summer: Invalid %s %v, must return T or (T, error)", kind, fnType))
+	}
+
+	return &factory{fn: reflect.ValueOf(fn), kind: kind, name: name}, nil
+}
+
+// The type of dependency the factory constructs
+func (f *factory) resultType() reflect.Type {
+	return f.fn.Type().Out(0)
+}
+
+func (f *factory) String() string {
+	if f.name == "" {
+		return fmt.Sprintf("%s for %s", f.kind, f.resultType())
+	}
+	return fmt.Sprintf("%s %s", f.kind, f.name)
+}
+
+// Registers a function that constructs a dependency the first time it's
+// needed, either by name or by the factory's result type. The factory must be
+// a func() T or a func() (T, error); the constructed dependency is then added
+// to the container as if by Add, so the factory runs at most once. A factory
+// that returns an error is retried the next time the dependency is needed.
+//
+// An error is returned if factory doesn't have one of the supported
+// signatures.
+func (c *Container) AddFactory(factory interface{}, name string) error {
+	return c.addFactory(factory, kindFactory, name)
+}
+
+// Registers a provider, which behaves like a factory whose arguments are
+// supplied by the container. The provider must be a func(T1, T2, ...) R or
+// a func(T1, T2, ...) (R, error); each argument is resolved by type, exactly
+// as if it were an automatically injected field, when the provider's result
+// is first needed.
+//
+// An error is returned if provider doesn't have one of the supported
+// signatures. Arguments that can't be resolved are reported when the
+// provider's result is injected.
+func (c *Container) AddProvider(provider interface{}, name string) error {
+	return c.addFactory(provider, kindProvider, name)
+}
+
+// Registers a prototype, which behaves like a provider that runs every time
+// its result is needed rather than once. Each field injected with it, and
+// each call to Get, therefore receives a fresh instance. Prototypes take
+// the same forms as providers and aren't run by PerformInjections unless
+// something needs their result.
+//
+// An error is returned if factory doesn't have one of the supported
+// signatures.
+func (c *Container) AddPrototype(factory interface{}, name string) error {
+	return c.addFactory(factory, kindPrototype, name)
+}
+
+func (c *Container) addFactory(fn interface{}, kind string, name string) error {
+	if err := c.checkFrozen(); err != nil {
+		return err
+	}
+	f, err := newFactory(fn, kind, name)
+	if err != nil {
+		return err
+	}
+
+	if name != "" {
+		c.factories[name] = f
+	}
+	c.factoriesByType[f.resultType()] = f
+
+	return nil
+}
+
+// Calls the factory, resolving any arguments it needs first. On success the
+// result replaces the factory's registrations.
+func (c *Container) construct(f *factory, trace *resolutionTrace) (interface{}, bool) {
+	if c.parent != nil && f.kind != kindScoped && f.kind != kindPrototype {
+		return c.constructInParent(f, trace)
+	}
+
+	// Neither result exists yet, so a cycle can only be caught here
+	for _, constructing := range trace.constructing {
+		if constructing == f {
+			trace.add("which forms a cycle: %s", describeCycle(trace.constructing, f))
+			return nil, false
+		}
+	}
+	trace.add("via %s", f)
+
+	fnType := f.fn.Type()
+	args := make([]reflect.Value, fnType.NumIn())
+	trace.constructing = append(trace.constructing, f)
+	for index := range args {
+		hops := len(trace.hops)
+		argument, ok := c.resolveType(fnType.In(index), trace)
+		if !ok {
+			// Deeper failures have already explained themselves
+			if len(trace.hops) == hops {
+				trace.add("which needs missing %s", fnType.In(index))
+			}
+			return nil, false
+		}
+		args[index] = reflect.ValueOf(argument)
+	}
+	trace.constructing = trace.constructing[:len(trace.constructing)-1]
+
+	results, ok := c.callFactory(f, args)
+	if !ok {
+		trace.add("which timed out after %s", c.ConstructTimeout)
+		trace.timedOut = f
+		return nil, false
+	}
+	if len(results) == 2 && !results[1].IsNil() {
+		trace.add("which failed: %v", results[1].Interface())
+		return nil, false
+	}
+	dependency := results[0].Interface()
+
+	if f.kind == kindPrototype {
+		return dependency, true
+	}
+
+	c.replaceFactory(f, dependency)
+	return dependency, true
+}
+
+// Registers what the factory constructed in place of the factory
+func (c *Container) replaceFactory(f *factory, dependency interface{}) {
+	if c.factories[f.name] == f {
+		delete(c.factories, f.name)
+	}
+	if c.factoriesByType[f.resultType()] == f {
+		delete(c.factoriesByType, f.resultType())
+	}
+	c.register(dependency, f.name)
+	// Fields of the interface the factory returns must still find it by type
+	if f.resultType().Kind() == reflect.Interface {
+		c.dependenciesByInterface[f.resultType()] = dependency
+	}
+}
+
+// Calls the factory's function, giving up once ConstructTimeout has passed
+// if one is set. A function that times out is left running in the
+// background, and whatever it returns is discarded.
+func (c *Container) callFactory(f *factory, args []reflect.Value) ([]reflect.Value, bool) {
+	if c.ConstructTimeout <= 0 {
+		return f.fn.Call(args), true
+	}
+
+	type outcome struct {
+		results []reflect.Value
+		panic   interface{}
+	}
+	done := make(chan outcome, 1)
+	go func() {
+		defer func() {
+			if r := recover(); r != nil {
+				done <- outcome{panic: r}
+			}
+		}()
+		done <- outcome{results: f.fn.Call(args)}
+	}()
+
+	timer := time.NewTimer(c.ConstructTimeout)
+	defer timer.Stop()
+	select {
+	case result := <-done:
+		// Panics belong to the caller, as they would without a timeout
+		if result.panic != nil {
+			panic(result.panic)
+		}
+		return result.results, true
+	case <-timer.C:
+		return nil, false
+	}
+}
+
+// Returned by PerformInjections and InjectInto when a factory or provider
+// takes longer than the container's ConstructTimeout.
+type ConstructTimeoutError struct {
+	Factory string // Describes the factory or provider that timed out
+	Timeout time.Duration
+	trace   string
+}
+
+func (e *ConstructTimeoutError) Error() string {
+	return fmt.Sprintf("Summer: Unable to construct %s", e.trace)
+}
+
+// Constructs every pending factory and provider, invoking each only after
+// the providers of its arguments have run.
+func (c *Container) constructAll() error {
+	order, err := c.constructionOrder()
+	if err != nil {
+		return err
+	}
+
+	for _, f := range order {
+		if !c.isPending(f) {
+			continue
+		}
+
+		trace := &resolutionTrace{name: f.String()}
+		if _, ok := c.construct(f, trace); !ok {
+			if err := trace.timeoutError(c.ConstructTimeout); err != nil {
+				return err
+			}
+			return errors.New(fmt.Sprintf("Summer: Unable to construct %s", trace))
+		}
+	}
+
+	return nil
+}
+
+// Whether the factory is still waiting to be constructed. Prototypes are
+// never pending, since they construct on every use.
+func (c *Container) isPending(f *factory) bool {
+	return f.kind != kindPrototype &&
+		(c.factories[f.name] == f || c.factoriesByType[f.resultType()] == f)
+}
+
+// Sorts the pending factories so that each comes after the providers of its
+// arguments. An error listing the members of a cycle is returned if some
+// providers depend on each other.
+func (c *Container) constructionOrder() ([]*factory, error) {
+	var pending []*factory
+	seen := make(map[*factory]bool)
+	for _, f := range c.factories {
+		if !seen[f] && c.isPending(f) {
+			seen[f] = true
+			pending = append(pending, f)
+		}
+	}
+	for _, f := range c.factoriesByType {
+		if !seen[f] && c.isPending(f) {
+			seen[f] = true
+			pending = append(pending, f)
+		}
+	}
+	sort.Slice(pending, func(i, j int) bool {
+		return pending[i].String() < pending[j].String()
+	})
+
+	const (
+		unvisited = iota
+		visiting
+		visited
+	)
+	state := make(map[*factory]int)
+	var order, stack []*factory
+
+	var visit func(f *factory) error
+	visit = func(f *factory) error {
+		switch state[f] {
+		case visited:
+			return nil
+		case visiting:
+			return newCycleError(stack, f)
+		}
+
+		state[f] = visiting
+		stack = append(stack, f)
+		for _, dependency := range c.factoryDependencies(f) {
+			if err := visit(dependency); err != nil {
+				return err
+			}
+		}
+		stack = stack[:len(stack)-1]
+		state[f] = visited
+		order = append(order, f)
+
+		return nil
+	}
+
+	for _, f := range pending {
+		if err := visit(f); err != nil {
+			return nil, err
+		}
+	}
+
+	return order, nil
+}
+
+// The pending factories that must run before f can be called
+func (c *Container) factoryDependencies(f *factory) []*factory {
+	var dependencies []*factory
+	fnType := f.fn.Type()
+
+	for index := 0; index < fnType.NumIn(); index++ {
+		argumentType := fnType.In(index)
+		if _, ok := c.lookupType(argumentType); ok {
+			continue
+		}
+		if dependency, ok := c.factoriesByType[argumentType]; ok && c.isPending(dependency) {
+			dependencies = append(dependencies, dependency)
+		}
+	}
+
+	return dependencies
+}
+
+// Describes the cycle formed by the tail of stack starting at f
+func newCycleError(stack []*factory, f *factory) error {
+	return errors.New(
+		fmt.Sprintf("Summer: Dependency cycle between providers: %s", describeCycle(stack, f)))
+}
+
+// Lists the tail of stack starting at f, then f again to close the cycle
+func describeCycle(stack []*factory, f *factory) string {
+	start := len(stack) - 1
+	for stack[start] != f {
+		start--
+	}
+
+	var members []string
+	for _, member := range stack[start:] {
+		members = append(members, member.String())
+	}
+	members = append(members, f.String())
+
+	return strings.Join(members, " -> ")
+}
+
+// Records each hop taken while resolving a dependency, so that a failed
+// resolution can explain how the container got there.
+type resolutionTrace struct {
+	name     string
+	hops     []string
+	timedOut *factory // The factory that exceeded ConstructTimeout, if any
+
+	// Factories whose arguments are being resolved, innermost last
+	constructing []*factory
+}
+
+func (t *resolutionTrace) add(format string, args ...interface{}) {
+	t.hops = append(t.hops, fmt.Sprintf(format, args...))
+}
+
+// Returns a ConstructTimeoutError if the resolution failed because a factory
+// exceeded timeout, or nil otherwise
+func (t *resolutionTrace) timeoutError(timeout time.Duration) error {
+	if t.timedOut == nil {
+		return nil
+	}
+
+	return &ConstructTimeoutError{
+		Factory: t.timedOut.String(),
+		Timeout: timeout,
+		trace:   t.String(),
+	}
+}
+
+// Formats as the requested dependency followed by each hop, e.g.
+// "DB (via factory DB, which failed: connection refused)"
+func (t *resolutionTrace) String() string {
+	if len(t.hops) == 0 {
+		return t.name
+	}
+
+	return fmt.Sprintf("%s (%s)", t.name, strings.Join(t.hops, ", "))
+}
+
+// Finds the named dependency, following aliases and constructing it with its
+// factory if necessary.
+// Any hops taken along the way are recorded in trace.
+func (c *Container) resolveName(name string, trace *resolutionTrace) (interface{}, bool) {
+	if dependency, ok := c.lookupName(name); ok {
+		return dependency, true
+	}
+
+	if existingName, ok := c.aliases[name]; ok {
+		trace.add("alias of %s", existingName)
+		return c.resolveName(existingName, trace)
+	}
+
+	if f, ok := c.factories[name]; ok {
+		return c.construct(f, trace)
+	}
+
+	return nil, false
+}
+
+// Whether anything is registered under name, without constructing it
+func (c *Container) hasName(name string) bool {
+	_, registered := c.lookupName(name)
+	_, aliased := c.aliases[name]
+	_, pending := c.factories[name]
+
+	return registered || aliased || pending
+}
+
+// Finds a dependency by its exact type, constructing it with a factory if
+// necessary. Any hops taken along the way are recorded in trace.
+func (c *Container) resolveType(t reflect.Type, trace *resolutionTrace) (interface{}, bool) {
+	if dependency, ok := c.lookupType(t); ok {
+		return dependency, true
+	}
+	if dependency, ok := c.dependenciesByInterface[t]; ok {
+		return dependency, true
+	}
+
+	if f, ok := c.factoriesByType[t]; ok {
+		return c.construct(f, trace)
+	}
+
+	return nil, false
+}
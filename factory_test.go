@@ -0,0 +1,387 @@
+package summer
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestFactoryConstructsOnce(t *testing.T) {
+	type simpleStruct struct {
+		Value *int `summer:"Value"`
+	}
+	calls := 0
+
+	container := NewContainer()
+	err := container.AddFactory(func() *int {
+		calls++
+		return new(int)
+	}, "Value")
+
+	s1, s2 := new(simpleStruct), new(simpleStruct)
+	err1 := container.InjectInto(s1)
+	err2 := container.InjectInto(s2)
+
+	if err != nil || err1 != nil || err2 != nil || calls != 1 || s1.Value == nil || s1.Value != s2.Value {
+		t.Fail()
+	}
+}
+
+func TestRejectsInvalidFactory(t *testing.T) {
+	container := NewContainer()
+
+	if container.AddFactory("not a function", "Value") == nil {
+		t.Fail()
+	}
+	if container.AddFactory(func(x int) int { return x }, "Value") == nil {
+		t.Fail()
+	}
+	if container.AddFactory(func() (int, int) { return 0, 0 }, "Value") == nil {
+		t.Fail()
+	}
+}
+
+func TestFailedFactoryTracedInError(t *testing.T) {
+	type simpleStruct struct {
+		DB string `summer:"DB"`
+	}
+
+	container := NewContainer()
+	container.AddFactory(func() (string, error) {
+		return "", errors.New("connection refused")
+	}, "DB")
+	err := container.InjectInto(new(simpleStruct))
+
+	if err == nil || !strings.Contains(err.Error(), "DB (via factory DB, which failed: connection refused)") {
+		t.Log(err)
+		t.Fail()
+	}
+}
+
+type providedDB struct {
+	URL string
+}
+
+type providedRepository struct {
+	DB *providedDB
+}
+
+type providedService struct {
+	Repository *providedRepository
+	Name       string
+}
+
+func TestProviderWithoutParameters(t *testing.T) {
+	type simpleStruct struct {
+		DB *providedDB `summer:"DB"`
+	}
+
+	container := NewContainer()
+	err := container.AddProvider(func() *providedDB {
+		return &providedDB{URL: "db://"}
+	}, "DB")
+	s := new(simpleStruct)
+	injectErr := container.InjectInto(s)
+
+	if err != nil || injectErr != nil || s.DB == nil || s.DB.URL != "db://" {
+		t.Log(injectErr)
+		t.Fail()
+	}
+}
+
+func TestProviderWithOneParameter(t *testing.T) {
+	type simpleStruct struct {
+		Repository *providedRepository `summer:"Repository"`
+	}
+	db := new(providedDB)
+
+	container := NewContainer()
+	container.Add(db, "")
+	container.AddProvider(func(db *providedDB) *providedRepository {
+		return &providedRepository{DB: db}
+	}, "Repository")
+	s := new(simpleStruct)
+	err := container.InjectInto(s)
+
+	if err != nil || s.Repository == nil || s.Repository.DB != db {
+		t.Log(err)
+		t.Fail()
+	}
+}
+
+func TestProviderWithTwoParameters(t *testing.T) {
+	type simpleStruct struct {
+		Service *providedService `summer:",auto"`
+	}
+	repository := new(providedRepository)
+
+	container := NewContainer()
+	container.Add(repository, "")
+	container.Add("service name", "")
+	container.AddProvider(func(r *providedRepository, name string) (*providedService, error) {
+		return &providedService{Repository: r, Name: name}, nil
+	}, "")
+	s := new(simpleStruct)
+	err := container.InjectInto(s)
+
+	if err != nil || s.Service == nil || s.Service.Repository != repository ||
+		s.Service.Name != "service name" {
+		t.Log(err)
+		t.Fail()
+	}
+}
+
+func TestProviderWithUnresolvableParameter(t *testing.T) {
+	type simpleStruct struct {
+		Repository *providedRepository `summer:"Repository"`
+	}
+
+	container := NewContainer()
+	container.AddProvider(func(db *providedDB) *providedRepository {
+		return &providedRepository{DB: db}
+	}, "Repository")
+	err := container.InjectInto(new(simpleStruct))
+
+	if err == nil || !strings.Contains(err.Error(), "which needs missing *summer.providedDB") {
+		t.Log(err)
+		t.Fail()
+	}
+}
+
+func TestFailedProviderChainTracedInError(t *testing.T) {
+	type simpleStruct struct {
+		Service *providedService `summer:"Service"`
+	}
+
+	container := NewContainer()
+	container.AddProvider(func(r *providedRepository) *providedService {
+		return &providedService{Repository: r}
+	}, "Service")
+	container.AddProvider(func(db *providedDB) *providedRepository {
+		return &providedRepository{DB: db}
+	}, "")
+	container.AddFactory(func() (*providedDB, error) {
+		return nil, errors.New("connection refused")
+	}, "")
+	err := container.InjectInto(new(simpleStruct))
+
+	expected := "Service (via provider Service, via provider for *summer.providedRepository," +
+		" via factory for *summer.providedDB, which failed: connection refused)"
+	if err == nil || !strings.Contains(err.Error(), expected) {
+		t.Log(err)
+		t.Fail()
+	}
+}
+
+func TestPerformInjectionsConstructsProvidersInOrder(t *testing.T) {
+	type simpleStruct struct {
+		Service *providedService `summer:",auto"`
+	}
+	var calls []string
+
+	container := NewContainer()
+	container.AddProvider(func(r *providedRepository) *providedService {
+		calls = append(calls, "service")
+		return &providedService{Repository: r}
+	}, "")
+	container.AddProvider(func(db *providedDB) *providedRepository {
+		calls = append(calls, "repository")
+		return &providedRepository{DB: db}
+	}, "")
+	container.AddProvider(func() *providedDB {
+		calls = append(calls, "db")
+		return new(providedDB)
+	}, "")
+	s := new(simpleStruct)
+	container.Add(s, "")
+	err := container.PerformInjections()
+
+	if err != nil || strings.Join(calls, ",") != "db,repository,service" ||
+		s.Service == nil || s.Service.Repository == nil || s.Service.Repository.DB == nil {
+		t.Log(err, calls)
+		t.Fail()
+	}
+}
+
+type cyclicProvidedA struct{}
+type cyclicProvidedB struct{}
+
+func TestPerformInjectionsReportsProviderCycle(t *testing.T) {
+	container := NewContainer()
+	container.AddProvider(func(*cyclicProvidedB) *cyclicProvidedA {
+		return new(cyclicProvidedA)
+	}, "")
+	container.AddProvider(func(*cyclicProvidedA) *cyclicProvidedB {
+		return new(cyclicProvidedB)
+	}, "")
+	err := container.PerformInjections()
+
+	expected := "provider for *summer.cyclicProvidedA -> provider for *summer.cyclicProvidedB" +
+		" -> provider for *summer.cyclicProvidedA"
+	if err == nil || !strings.Contains(err.Error(), expected) {
+		t.Log(err)
+		t.Fail()
+	}
+}
+
+func TestInjectIntoReportsProviderCycle(t *testing.T) {
+	type simpleStruct struct {
+		A *cyclicProvidedA `summer:",auto"`
+	}
+
+	container := NewContainer()
+	container.AddProvider(func(*cyclicProvidedB) *cyclicProvidedA {
+		return new(cyclicProvidedA)
+	}, "")
+	container.AddProvider(func(*cyclicProvidedA) *cyclicProvidedB {
+		return new(cyclicProvidedB)
+	}, "")
+	err := container.InjectInto(new(simpleStruct))
+
+	expected := "which forms a cycle: provider for *summer.cyclicProvidedA" +
+		" -> provider for *summer.cyclicProvidedB -> provider for *summer.cyclicProvidedA"
+	if err == nil || !strings.Contains(err.Error(), expected) {
+		t.Log(err)
+		t.Fail()
+	}
+}
+
+func TestFailedAliasedFactoryTracedInError(t *testing.T) {
+	type simpleStruct struct {
+		DB string `summer:"DB"`
+	}
+
+	container := NewContainer()
+	container.AddFactory(func() (string, error) {
+		return "", errors.New("connection refused")
+	}, "PrimaryDB")
+	container.Alias("DB", "PrimaryDB")
+	err := container.InjectInto(new(simpleStruct))
+
+	expected := "DB (alias of PrimaryDB, via factory PrimaryDB, which failed: connection refused)"
+	if err == nil || !strings.Contains(err.Error(), expected) {
+		t.Log(err)
+		t.Fail()
+	}
+}
+
+func TestPrototypeConstructsPerInjection(t *testing.T) {
+	type namedStruct struct {
+		DB *providedDB `summer:"DB"`
+	}
+	type autoStruct struct {
+		DB *providedDB `summer:",auto"`
+	}
+	calls := 0
+
+	container := NewContainer()
+	err := container.AddPrototype(func() *providedDB {
+		calls++
+		return new(providedDB)
+	}, "DB")
+	n1, n2, a := new(namedStruct), new(namedStruct), new(autoStruct)
+	container.Add(n1, "")
+	container.Add(n2, "")
+	injectErr := container.PerformInjections()
+	autoErr := container.InjectInto(a)
+
+	if err != nil || injectErr != nil || autoErr != nil || calls != 3 ||
+		n1.DB == nil || n2.DB == nil || a.DB == nil ||
+		n1.DB == n2.DB || n1.DB == a.DB || n2.DB == a.DB {
+		t.Log(injectErr, autoErr, calls)
+		t.Fail()
+	}
+}
+
+func TestConstructTimeout(t *testing.T) {
+	container := NewContainer()
+	container.ConstructTimeout = 10 * time.Millisecond
+	release := make(chan struct{})
+	defer close(release)
+	container.AddFactory(func() *redisCache {
+		<-release
+		return new(redisCache)
+	}, "cache")
+	err := container.PerformInjections()
+
+	var timeout *ConstructTimeoutError
+	if !errors.As(err, &timeout) || timeout.Factory != "factory cache" ||
+		timeout.Timeout != 10*time.Millisecond || !strings.Contains(err.Error(), "timed out after 10ms") {
+		t.Log(err)
+		t.Fail()
+	}
+}
+
+func TestConstructTimeoutFromInjectInto(t *testing.T) {
+	container := NewContainer()
+	container.ConstructTimeout = 10 * time.Millisecond
+	release := make(chan struct{})
+	defer close(release)
+	container.AddFactory(func() *redisCache {
+		<-release
+		return new(redisCache)
+	}, "cache")
+	namedErr := container.InjectInto(&struct {
+		Cache *redisCache `summer:"cache"`
+	}{})
+	autoErr := container.InjectInto(&struct {
+		Cache *redisCache `summer:",auto"`
+	}{})
+
+	var named, auto *ConstructTimeoutError
+	if !errors.As(namedErr, &named) || named.Factory != "factory cache" ||
+		!errors.As(autoErr, &auto) || auto.Timeout != 10*time.Millisecond {
+		t.Log(namedErr, autoErr)
+		t.Fail()
+	}
+}
+
+func TestConstructTimeoutAllowsFastFactories(t *testing.T) {
+	container := NewContainer()
+	container.ConstructTimeout = time.Second
+	container.AddFactory(func() *redisCache { return new(redisCache) }, "cache")
+	err := container.PerformInjections()
+
+	if _, ok := container.Get("cache"); err != nil || !ok {
+		t.Log(err)
+		t.Fail()
+	}
+}
+
+func TestAutoInjectionAfterEveryRegistrationPath(t *testing.T) {
+	type simpleStruct struct {
+		Handler testHandler `summer:",auto"`
+	}
+
+	paths := map[string]func(c *Container){
+		"add": func(c *Container) {
+			AddAs[testHandler](c, namedHandler("a"), "handler")
+		},
+		"alias": func(c *Container) {
+			AddAs[testHandler](c, namedHandler("a"), "handler")
+			c.Alias("alias", "handler")
+			c.Get("alias")
+		},
+		"factory": func(c *Container) {
+			c.AddFactory(func() testHandler { return namedHandler("a") }, "handler")
+			c.Get("handler")
+		},
+		"provider": func(c *Container) {
+			c.AddProvider(func() (testHandler, error) { return namedHandler("a"), nil }, "handler")
+			c.Get("handler")
+		},
+	}
+
+	for path, register := range paths {
+		container := NewContainer()
+		register(container)
+		s := new(simpleStruct)
+		err := container.InjectInto(s)
+
+		if err != nil || s.Handler != namedHandler("a") {
+			t.Log(path, err)
+			t.Fail()
+		}
+	}
+}
@@ -0,0 +1,45 @@
+package summer
+
+import (
+	"reflect"
+	"time"
+)
+
+// The number of events History keeps when the container's HistoryLimit
+// isn't set
+const DefaultHistoryLimit = 1000
+
+// A field set during injection, as recorded while TrackHistory is set
+type InjectionEvent struct {
+	Target     reflect.Type // The struct injected into
+	Field      string
+	Dependency reflect.Type // The injected value's type, the implementation's for interfaces
+	Time       time.Time    // When the field was set, by the container's clock
+}
+
+// Returns the fields set since TrackHistory was enabled, oldest first. Once
+// the limit is reached the oldest events are dropped, see HistoryLimit.
+func (c *Container) History() []InjectionEvent {
+	return append([]InjectionEvent(nil), c.history...)
+}
+
+func (c *Container) recordHistory(p injectionPoint, dep reflect.Type) {
+	if !c.TrackHistory {
+		return
+	}
+
+	limit := c.HistoryLimit
+	if limit <= 0 {
+		limit = DefaultHistoryLimit
+	}
+	if len(c.history) >= limit {
+		c.history = append(c.history[:0], c.history[len(c.history)-limit+1:]...)
+	}
+
+	c.history = append(c.history, InjectionEvent{
+		Target:     p.elementType,
+		Field:      p.typeField.Name,
+		Dependency: dep,
+		Time:       c.clock.Now(),
+	})
+}
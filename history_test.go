@@ -0,0 +1,68 @@
+package summer
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestHistoryRecordsInjections(t *testing.T) {
+	type simpleStruct struct {
+		Name  string       `summer:"name"`
+		Cache *memoryCache `summer:",auto"`
+	}
+	clock := &fakeClock{now: time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)}
+	cache := new(memoryCache)
+
+	container := NewContainer()
+	container.TrackHistory = true
+	container.SetClock(clock)
+	container.Add("value", "name")
+	container.Add(cache, "")
+	container.Add(new(simpleStruct), "")
+	err := container.PerformInjections()
+
+	structType := reflect.TypeOf(simpleStruct{})
+	expected := []InjectionEvent{
+		{Target: structType, Field: "Name", Dependency: reflect.TypeOf(""), Time: clock.now},
+		{Target: structType, Field: "Cache", Dependency: reflect.TypeOf(cache), Time: clock.now},
+	}
+	if err != nil || !reflect.DeepEqual(container.History(), expected) {
+		t.Log(err, container.History())
+		t.Fail()
+	}
+}
+
+func TestHistoryKeepsMostRecentEvents(t *testing.T) {
+	type simpleStruct struct {
+		A string `summer:"name"`
+		B string `summer:"name"`
+		C string `summer:"name"`
+	}
+
+	container := NewContainer()
+	container.TrackHistory = true
+	container.HistoryLimit = 2
+	container.Add("value", "name")
+	err := container.InjectInto(new(simpleStruct))
+
+	history := container.History()
+	if err != nil || len(history) != 2 || history[0].Field != "B" || history[1].Field != "C" {
+		t.Log(err, history)
+		t.Fail()
+	}
+}
+
+func TestHistoryOffByDefault(t *testing.T) {
+	type simpleStruct struct {
+		Name string `summer:"name"`
+	}
+
+	container := NewContainer()
+	container.Add("value", "name")
+	container.InjectInto(new(simpleStruct))
+
+	if len(container.History()) != 0 {
+		t.Fail()
+	}
+}
@@ -0,0 +1,75 @@
+package summer
+
+import "reflect"
+
+// Describes the field a value is about to be injected into
+type InjectionContext struct {
+	FieldName string
+	FieldType reflect.Type
+}
+
+// Observes or replaces a value before it's injected, see Container.Use
+type Interceptor func(p InjectionContext, value interface{}) (interface{}, error)
+
+// Registers an interceptor that's called with every value right before it's
+// set into a field. The value the interceptor returns is injected in place
+// of the original, and must still be assignable to the field. Returning an
+// error aborts the injection with that error.
+//
+// Interceptors run in the order they were registered, each receiving the
+// value returned by the one before.
+func (c *Container) Use(fn Interceptor) {
+	c.interceptors = append(c.interceptors, fn)
+}
+
+// Sets the field to value, once it has passed through every interceptor
+func (c *Container) setField(p injectionPoint, value reflect.Value) error {
+	// Nil interfaces registered with AddTyped have no value of their own
+	if !value.IsValid() {
+		value = reflect.Zero(p.typeField.Type)
+	}
+
+	if len(c.interceptors) > 0 {
+		context := InjectionContext{FieldName: p.typeField.Name, FieldType: p.typeField.Type}
+		current := value.Interface()
+		for _, interceptor := range c.interceptors {
+			var err error
+			if current, err = interceptor(context, current); err != nil {
+				return err
+			}
+		}
+
+		if current == nil {
+			value = reflect.Zero(p.typeField.Type)
+		} else if reflect.TypeOf(current).AssignableTo(p.typeField.Type) {
+			value = reflect.ValueOf(current)
+		} else {
+			return newTypeMismatchError(p, current)
+		}
+	}
+
+	c.mutex.Lock()
+	if p.tag.setter != "" {
+		if err := callSetter(p, value); err != nil {
+			c.mutex.Unlock()
+			return err
+		}
+	} else {
+		p.field.Set(value)
+	}
+	if c.CollectStats {
+		c.stats.FieldsSet++
+	}
+
+	dep := value.Type()
+	if value.Kind() == reflect.Interface && !value.IsNil() {
+		dep = value.Elem().Type()
+	}
+	c.recordHistory(p, dep)
+	c.mutex.Unlock()
+
+	if len(c.observers) > 0 {
+		c.notifyInject(p, dep)
+	}
+	return nil
+}
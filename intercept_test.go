@@ -0,0 +1,111 @@
+package summer
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+type wrappedHandler struct {
+	inner testHandler
+}
+
+func (w *wrappedHandler) Handle() string {
+	return "wrapped " + w.inner.Handle()
+}
+
+func TestInterceptorSubstitutesValue(t *testing.T) {
+	type simpleStruct struct {
+		Handler testHandler `summer:"handler"`
+	}
+
+	container := NewContainer()
+	container.Add(namedHandler("a"), "handler")
+	var seen InjectionContext
+	container.Use(func(p InjectionContext, value interface{}) (interface{}, error) {
+		seen = p
+		if handler, ok := value.(testHandler); ok {
+			return &wrappedHandler{inner: handler}, nil
+		}
+		return value, nil
+	})
+	s := new(simpleStruct)
+	err := container.InjectInto(s)
+
+	if err != nil || seen.FieldName != "Handler" {
+		t.Log(err)
+		t.Fail()
+	}
+	if _, ok := s.Handler.(*wrappedHandler); !ok {
+		t.Fail()
+	}
+}
+
+func TestInterceptorErrorAbortsInjection(t *testing.T) {
+	type simpleStruct struct {
+		Handler testHandler `summer:"handler"`
+	}
+
+	rejected := errors.New("rejected")
+	container := NewContainer()
+	container.Add(namedHandler("a"), "handler")
+	container.Use(func(p InjectionContext, value interface{}) (interface{}, error) {
+		return nil, rejected
+	})
+	s := new(simpleStruct)
+	err := container.InjectInto(s)
+
+	if err != rejected || s.Handler != nil {
+		t.Log(err)
+		t.Fail()
+	}
+}
+
+func TestInterceptorMustReturnAssignableValue(t *testing.T) {
+	type simpleStruct struct {
+		Handler testHandler `summer:"handler"`
+	}
+
+	container := NewContainer()
+	container.Add(namedHandler("a"), "handler")
+	container.Use(func(p InjectionContext, value interface{}) (interface{}, error) {
+		return "not a handler", nil
+	})
+	err := container.InjectInto(new(simpleStruct))
+
+	var mismatch *TypeMismatchError
+	if !errors.As(err, &mismatch) {
+		t.Log(err)
+		t.Fail()
+	}
+}
+
+func TestFieldsInjectedInTagOrder(t *testing.T) {
+	type simpleStruct struct {
+		A string `summer:"a,,order=2"`
+		B string `summer:"b,,order=1"`
+		C string `summer:"c"`
+	}
+	var injected []string
+
+	container := NewContainer()
+	container.AddNamed(map[string]interface{}{"a": "a", "b": "b", "c": "c"})
+	container.Use(func(p InjectionContext, value interface{}) (interface{}, error) {
+		injected = append(injected, p.FieldName)
+		return value, nil
+	})
+	err := container.InjectInto(new(simpleStruct))
+
+	if err != nil || strings.Join(injected, ",") != "C,B,A" {
+		t.Log(err, injected)
+		t.Fail()
+	}
+}
+
+func TestInvalidFieldOrderIsUnknownOption(t *testing.T) {
+	tag := parseFieldTag("a,order=first")
+
+	if len(tag.unknown) != 1 || tag.order != 0 {
+		t.Fail()
+	}
+}
@@ -0,0 +1,420 @@
+package summer
+
+import (
+	"bytes"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// Returns the dependencies that target's tagged fields would need but that
+// the container can't currently supply: the dependency name for fields
+// injected by name, or the type for fields injected automatically. The
+// target isn't modified, and nothing is constructed by factories.
+//
+// An error is returned if the target is not a pointer-to-struct.
+func (c *Container) MissingDependencies(target interface{}) ([]string, error) {
+	if err := checkInspectable(target); err != nil {
+		return nil, err
+	}
+
+	missing := []string{}
+	iterateTaggedFields(target, func(p injectionPoint) error {
+		p.tag = c.fieldTagFor(p.typeField)
+		if p.tag != nil && p.isSettable() && !c.canInject(p) {
+			missing = append(missing, describeDependency(p))
+		}
+		return nil
+	})
+
+	return missing, nil
+}
+
+// Returns an error wrapping ErrNotAStruct unless target is a non-nil
+// pointer-to-struct
+func checkInspectable(target interface{}) error {
+	if !isPointerToStruct(target) {
+		return fmt.Errorf("Summer: Attempted to inspect something other than a pointer-to-struct: %w",
+			ErrNotAStruct)
+	}
+	if reflect.ValueOf(target).IsNil() {
+		return fmt.Errorf("Summer: Attempted to inspect a nil %T: %w", target, ErrNotAStruct)
+	}
+
+	return nil
+}
+
+// A field's injection as planned by Plan
+type PlannedInjection struct {
+	Field string
+
+	// How the field would be injected: "name", "auto", "strategy", "self",
+	// or "inject" for fields only injected into
+	Mode string
+
+	// The name the dependency would be found by, blank unless Mode is "name"
+	DependencyName string
+
+	// The type of the dependency that would be injected, or nil if none
+	// would be found. The field's own type is given when the dependency
+	// would only be built at injection time.
+	DependencyType reflect.Type
+}
+
+// Describes what InjectInto would do with each of target's tagged fields,
+// without modifying the target or constructing anything. Fields that would
+// fail to inject are included with a nil DependencyType.
+//
+// An error is returned if the target is not a pointer-to-struct.
+func (c *Container) Plan(target interface{}) ([]PlannedInjection, error) {
+	if err := checkInspectable(target); err != nil {
+		return nil, err
+	}
+
+	plan := []PlannedInjection{}
+	iterateTaggedFields(target, func(p injectionPoint) error {
+		p.tag = c.fieldTagFor(p.typeField)
+		if p.tag == nil || !p.isSettable() {
+			return nil
+		}
+
+		planned := PlannedInjection{Field: p.typeField.Name, Mode: "auto"}
+		switch {
+		case p.tag.self:
+			planned.Mode = "self"
+		case p.tag.isInjectOnly():
+			planned.Mode = "inject"
+		case p.tag.strategy != "":
+			planned.Mode = "strategy"
+		case p.tag.dependencyName != "" && (!p.tag.autoInject || c.hasName(p.tag.dependencyName)):
+			planned.Mode = "name"
+			planned.DependencyName = p.tag.dependencyName
+		}
+
+		if dependency, ok := c.peekDependency(p); ok && dependency != nil {
+			planned.DependencyType = reflect.TypeOf(dependency)
+		} else if c.canInject(p) {
+			planned.DependencyType = p.typeField.Type
+		}
+
+		plan = append(plan, planned)
+		return nil
+	})
+
+	return plan, nil
+}
+
+// Calls fn with every field of target, its raw summer tag, which is blank if
+// it has none, and whether the container could currently inject it. Fields
+// that wouldn't be injected at all, such as untagged or unexported ones, are
+// reported as unresolvable. Like Plan, this doesn't modify the target or
+// construct anything, but it leaves interpreting the tags to the caller.
+//
+// An error is returned if the target is not a pointer-to-struct.
+func (c *Container) WalkInjectionPoints(target interface{}, fn func(field string, tag string, resolvable bool)) error {
+	if err := checkInspectable(target); err != nil {
+		return err
+	}
+
+	return iterateFields(target, func(p injectionPoint) error {
+		p.tag = c.fieldTagFor(p.typeField)
+		resolvable := p.tag != nil && p.isSettable() && c.canInject(p)
+		fn(p.typeField.Name, p.typeField.Tag.Get(summerTag), resolvable)
+		return nil
+	})
+}
+
+// Describes the dependency a tagged field asks for
+func describeDependency(p injectionPoint) string {
+	if p.tag.dependencyName != "" {
+		return p.tag.dependencyName
+	}
+	return "type " + p.typeField.Type.String()
+}
+
+// Whether the container has what it needs to inject the tagged field,
+// without constructing or setting anything
+func (c *Container) canInject(p injectionPoint) bool {
+	if p.tag.isInjectOnly() {
+		return true
+	}
+	if p.tag.self {
+		return reflect.TypeOf(p.target).AssignableTo(p.typeField.Type)
+	}
+	if p.tag.strategy == strategyWeighted {
+		return len(c.weightedDependencies[p.typeField.Type]) > 0
+	}
+	if p.tag.strategy != "" {
+		_, err := c.selectStrategy(p, p.tag.strategy)
+		return err == nil
+	}
+
+	if p.tag.dependencyName != "" {
+		if c.hasName(p.tag.dependencyName) {
+			return true
+		}
+		if name, _, ok := parseIndexedName(p.tag.dependencyName); ok && c.hasName(name) {
+			return true
+		}
+		if ordinal, ok := parseOrdinal(p.tag.dependencyName); ok {
+			return ordinal >= 1 && ordinal <= len(c.assignableDependencies(p.typeField.Type))
+		}
+		if !p.tag.autoInject {
+			return false
+		}
+	}
+
+	if p.tag.typeName != "" {
+		for dependencyType := range c.activeTypes() {
+			if dependencyType != nil && dependencyType.String() == p.tag.typeName {
+				return dependencyType.AssignableTo(p.typeField.Type)
+			}
+		}
+		return false
+	}
+
+	_, underlying := c.resolveUnderlying(p.typeField.Type)
+	_, matched := c.resolveMatched(p.typeField.Type)
+	return c.hasType(p.typeField.Type) || c.hasCounterpart(p.typeField.Type) || underlying || matched ||
+		c.hasBidirectional(p.typeField.Type) || p.tag.orNew ||
+		p.typeField.Type.Kind() == reflect.Map || p.typeField.Type.Kind() == reflect.Array ||
+		p.typeField.Type.Kind() == reflect.Slice
+}
+
+// Whether anything is registered under the exact type, without constructing
+// it
+func (c *Container) hasType(t reflect.Type) bool {
+	_, registered := c.lookupType(t)
+	_, registeredAs := c.dependenciesByInterface[t]
+	_, pending := c.factoriesByType[t]
+
+	return registered || registeredAs || pending
+}
+
+// Whether a struct's pointer, or a struct or interface pointer's value, is
+// registered
+func (c *Container) hasCounterpart(t reflect.Type) bool {
+	if t.Kind() == reflect.Struct {
+		return c.hasType(reflect.PtrTo(t))
+	} else if t.Kind() == reflect.Ptr &&
+		(t.Elem().Kind() == reflect.Struct || t.Elem().Kind() == reflect.Interface) {
+		return c.hasType(t.Elem())
+	}
+	return false
+}
+
+// Whether a directional channel's bidirectional counterpart is registered
+func (c *Container) hasBidirectional(t reflect.Type) bool {
+	return t.Kind() == reflect.Chan && t.ChanDir() != reflect.BothDir &&
+		c.hasType(reflect.ChanOf(reflect.BothDir, t.Elem()))
+}
+
+// Finds the dependency that would be injected into the tagged field without
+// constructing anything. False is returned both when the field can't be
+// injected and when its value would be built at injection time, as with
+// map, array and slice fields.
+func (c *Container) peekDependency(p injectionPoint) (interface{}, bool) {
+	if p.tag.strategy != "" || p.tag.isInjectOnly() {
+		return nil, false
+	}
+	if p.tag.self {
+		return p.target, c.canInject(p)
+	}
+
+	if p.tag.dependencyName != "" {
+		name := p.tag.dependencyName
+		for !c.isDirectName(name) && c.aliases[name] != "" {
+			name = c.aliases[name]
+		}
+		if dependency, ok := c.lookupName(name); ok {
+			return dependency, true
+		}
+		if !p.tag.autoInject {
+			return nil, false
+		}
+	}
+
+	if p.tag.typeName != "" {
+		for dependencyType, dependency := range c.activeTypes() {
+			if dependencyType != nil && dependencyType.String() == p.tag.typeName {
+				return dependency, true
+			}
+		}
+		return nil, false
+	}
+
+	if dependency, ok := c.lookupType(p.typeField.Type); ok {
+		return dependency, true
+	}
+	if dependency, ok := c.dependenciesByInterface[p.typeField.Type]; ok {
+		return dependency, true
+	}
+	if c.hasBidirectional(p.typeField.Type) {
+		return c.lookupType(reflect.ChanOf(reflect.BothDir, p.typeField.Type.Elem()))
+	}
+	return nil, false
+}
+
+// Returns the type of the dependency registered as name, following aliases.
+// Nothing is constructed: for a factory or provider that hasn't been used
+// yet, the type it returns is given. The type is nil for a nil interface
+// added with AddTyped.
+func (c *Container) TypeOf(name string) (reflect.Type, bool) {
+	for !c.isDirectName(name) && c.aliases[name] != "" {
+		name = c.aliases[name]
+	}
+
+	if dependency, ok := c.lookupName(name); ok {
+		return reflect.TypeOf(dependency), true
+	}
+	if f, ok := c.factories[name]; ok {
+		return f.resultType(), true
+	}
+
+	return nil, false
+}
+
+// Whether the name is registered directly rather than as an alias
+func (c *Container) isDirectName(name string) bool {
+	_, ok := c.lookupName(name)
+	return ok
+}
+
+// Describes the container's dependencies in the Graphviz DOT language. Each
+// dependency is a node labelled with its type and names, and each tagged
+// field of a struct dependency is an edge to the dependency it would
+// receive. Fields the container can't satisfy are drawn as dashed edges to a
+// node named "missing".
+func (c *Container) ExportDOT() string {
+	dependencies := c.activeDependencies()
+	positions := positionsOf(dependencies)
+	namesOf := c.namesByIndex(dependencies)
+
+	var out bytes.Buffer
+	out.WriteString("digraph summer {\n")
+
+	for index, dependency := range dependencies {
+		names := namesOf[index]
+		label := strings.Join(append([]string{fmt.Sprintf("%T", dependency)}, names...), "\n")
+		fmt.Fprintf(&out, "\tn%d [label=%q];\n", index, label)
+	}
+
+	hasMissing := false
+	for index, dependency := range dependencies {
+		if !isPointerToStruct(dependency) || reflect.ValueOf(dependency).IsNil() {
+			continue
+		}
+
+		iterateTaggedFields(dependency, func(p injectionPoint) error {
+			p.tag = c.fieldTagFor(p.typeField)
+			if p.tag == nil || !p.field.CanSet() {
+				return nil
+			}
+
+			if resolved, ok := c.peekDependency(p); ok {
+				if !reflect.ValueOf(resolved).Comparable() {
+					return nil
+				}
+				if target, ok := positions[resolved]; ok {
+					fmt.Fprintf(&out, "\tn%d -> n%d [label=%q];\n", index, target, p.typeField.Name)
+				}
+			} else if !c.canInject(p) {
+				hasMissing = true
+				fmt.Fprintf(&out, "\tn%d -> missing [label=%q, style=dashed];\n", index, p.typeField.Name)
+			}
+			return nil
+		})
+	}
+
+	if hasMissing {
+		out.WriteString("\tmissing [shape=box, style=dashed];\n")
+	}
+	out.WriteString("}\n")
+
+	return out.String()
+}
+
+// The sorted names of each of the dependencies, indexed by its position
+// among them
+func (c *Container) namesByIndex(dependencies []interface{}) map[int][]string {
+	positions := positionsOf(dependencies)
+	namesOf := make(map[int][]string)
+	for name, dependency := range c.activeNames() {
+		if !reflect.ValueOf(dependency).Comparable() {
+			continue
+		}
+		if index, ok := positions[dependency]; ok {
+			namesOf[index] = append(namesOf[index], name)
+		}
+	}
+	for _, names := range namesOf {
+		sort.Strings(names)
+	}
+
+	return namesOf
+}
+
+// The position of each comparable dependency among dependencies
+func positionsOf(dependencies []interface{}) map[interface{}]int {
+	positions := make(map[interface{}]int, len(dependencies))
+	for index, dependency := range dependencies {
+		if reflect.ValueOf(dependency).Comparable() {
+			positions[dependency] = index
+		}
+	}
+	return positions
+}
+
+// The longest value, in characters, String shows before truncating it
+const maxDescribedValue = 40
+
+// Lists the container's dependencies for debugging: first each name with
+// the type and value of its dependency, sorted by name, then the type and
+// value of each dependency added without a name, sorted by type. Long
+// values are truncated.
+func (c *Container) String() string {
+	dependencies := c.activeDependencies()
+	namesOf := c.namesByIndex(dependencies)
+
+	var named, unnamed []string
+	for index, dependency := range dependencies {
+		description := fmt.Sprintf("%T = %s", dependency, c.describeValue(dependency))
+		for _, name := range namesOf[index] {
+			named = append(named, fmt.Sprintf("%s %s", name, description))
+		}
+		if len(namesOf[index]) == 0 {
+			unnamed = append(unnamed, description)
+		}
+	}
+	sort.Strings(named)
+	sort.Strings(unnamed)
+
+	var out bytes.Buffer
+	fmt.Fprintf(&out, "summer.Container with %d dependencies", len(dependencies))
+	for _, line := range named {
+		fmt.Fprintf(&out, "\n\t%s", line)
+	}
+	for _, line := range unnamed {
+		fmt.Fprintf(&out, "\n\t(unnamed) %s", line)
+	}
+
+	return out.String()
+}
+
+func (c *Container) describeValue(dependency interface{}) string {
+	// A container added to itself would otherwise describe itself forever
+	if other, ok := dependency.(*Container); ok && other == c {
+		return "(this container)"
+	}
+
+	value := fmt.Sprintf("%v", dependency)
+	if _, ok := dependency.(string); ok {
+		value = fmt.Sprintf("%q", dependency)
+	}
+	if runes := []rune(value); len(runes) > maxDescribedValue {
+		value = string(runes[:maxDescribedValue-3]) + "..."
+	}
+
+	return value
+}
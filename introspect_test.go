@@ -0,0 +1,241 @@
+package summer
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestMissingDependencies(t *testing.T) {
+	type simpleStruct struct {
+		Present     string `summer:"Present"`
+		MissingName string `summer:"MissingName"`
+		MissingType *int   `summer:",auto"`
+		Untagged    bool
+	}
+	s := new(simpleStruct)
+
+	container := NewContainer()
+	container.Add("here", "Present")
+	missing, err := container.MissingDependencies(s)
+
+	expected := []string{"MissingName", "type *int"}
+	if err != nil || !reflect.DeepEqual(missing, expected) || s.Present != "" {
+		t.Log(err, missing)
+		t.Fail()
+	}
+}
+
+func TestMissingDependenciesRejectsNonStruct(t *testing.T) {
+	container := NewContainer()
+	_, err := container.MissingDependencies("not a struct")
+	_, nilErr := container.MissingDependencies((*dotServer)(nil))
+
+	if err == nil || !errors.Is(nilErr, ErrNotAStruct) {
+		t.Log(nilErr)
+		t.Fail()
+	}
+}
+
+type dotServer struct {
+	Handler *dotHandler `summer:",auto"`
+	Port    int         `summer:"Port"`
+}
+
+type dotHandler struct {
+	Server *dotServer `summer:"Server"`
+}
+
+func TestExportDOT(t *testing.T) {
+	container := NewContainer()
+	container.Add(new(dotServer), "Server")
+	container.Add(new(dotHandler), "")
+	dot := container.ExportDOT()
+
+	expected := []string{
+		"digraph summer {\n",
+		`n0 [label="*summer.dotServer\nServer"];`,
+		`n1 [label="*summer.dotHandler"];`,
+		`n0 -> n1 [label="Handler"];`,
+		`n0 -> missing [label="Port", style=dashed];`,
+		`n1 -> n0 [label="Server"];`,
+		`missing [shape=box, style=dashed];`,
+	}
+	for _, line := range expected {
+		if !strings.Contains(dot, line) {
+			t.Log(dot)
+			t.Fatal("missing ", line)
+		}
+	}
+}
+
+func TestExportDOTSkipsNilPointers(t *testing.T) {
+	container := NewContainer()
+	container.AddTyped(nil, reflect.TypeOf((*dotServer)(nil)), "Server")
+	dot := container.ExportDOT()
+
+	if !strings.Contains(dot, `n0 [label="*summer.dotServer\nServer"];`) {
+		t.Log(dot)
+		t.Fail()
+	}
+}
+
+func TestMissingDependenciesAcceptsBidirectionalChannel(t *testing.T) {
+	type simpleStruct struct {
+		Receiver <-chan int `summer:",auto"`
+	}
+
+	container := NewContainer()
+	container.Add(make(chan int), "")
+	missing, err := container.MissingDependencies(new(simpleStruct))
+
+	if err != nil || len(missing) != 0 {
+		t.Log(err, missing)
+		t.Fail()
+	}
+}
+
+func TestPlan(t *testing.T) {
+	type simpleStruct struct {
+		Name    string       `summer:"name"`
+		Cache   *memoryCache `summer:",auto"`
+		Missing string       `summer:"missing"`
+		Handler testHandler  `summer:"handler,auto"`
+		Skipped string
+	}
+	s := new(simpleStruct)
+
+	container := NewContainer()
+	container.Add("value", "name")
+	cache := new(memoryCache)
+	container.Add(cache, "")
+	plan, err := container.Plan(s)
+
+	expected := []PlannedInjection{
+		{Field: "Name", Mode: "name", DependencyName: "name", DependencyType: reflect.TypeOf("")},
+		{Field: "Cache", Mode: "auto", DependencyType: reflect.TypeOf(cache)},
+		{Field: "Missing", Mode: "name", DependencyName: "missing"},
+		{Field: "Handler", Mode: "auto"},
+	}
+	if err != nil || !reflect.DeepEqual(plan, expected) || s.Name != "" || s.Cache != nil {
+		t.Log(err, plan)
+		t.Fail()
+	}
+}
+
+func TestPlanRejectsNilPointers(t *testing.T) {
+	container := NewContainer()
+	_, err := container.Plan((*dotServer)(nil))
+
+	if !errors.Is(err, ErrNotAStruct) {
+		t.Log(err)
+		t.Fail()
+	}
+}
+
+func TestWalkInjectionPoints(t *testing.T) {
+	type simpleStruct struct {
+		Name    string       `summer:"name"`
+		Cache   *memoryCache `summer:",auto"`
+		Missing string       `summer:"missing"`
+		Skipped string
+		hidden  string `summer:"name"`
+	}
+	s := new(simpleStruct)
+
+	container := NewContainer()
+	container.Add("value", "name")
+	container.Add(new(memoryCache), "")
+	var walked []string
+	err := container.WalkInjectionPoints(s, func(field string, tag string, resolvable bool) {
+		walked = append(walked, fmt.Sprintf("%s %q %t", field, tag, resolvable))
+	})
+
+	expected := []string{
+		`Name "name" true`,
+		`Cache ",auto" true`,
+		`Missing "missing" false`,
+		`Skipped "" false`,
+		`hidden "name" false`,
+	}
+	if err != nil || !reflect.DeepEqual(walked, expected) || s.Name != "" || s.Cache != nil {
+		t.Log(err, walked)
+		t.Fail()
+	}
+}
+
+func TestTypeOf(t *testing.T) {
+	container := NewContainer()
+	container.Add(new(memoryCache), "cache")
+	container.Add("value", "name")
+	container.Alias("other", "name")
+	container.AddTyped(nil, reflect.TypeOf((*redisCache)(nil)), "redis")
+
+	cacheType, cacheOk := container.TypeOf("cache")
+	nameType, nameOk := container.TypeOf("other")
+	redisType, redisOk := container.TypeOf("redis")
+	_, missingOk := container.TypeOf("missing")
+	if !cacheOk || cacheType != reflect.TypeOf(new(memoryCache)) ||
+		!nameOk || nameType != reflect.TypeOf("") ||
+		!redisOk || redisType != reflect.TypeOf((*redisCache)(nil)) || missingOk {
+		t.Fail()
+	}
+}
+
+func TestTypeOfDoesNotConstruct(t *testing.T) {
+	container := NewContainer()
+	calls := 0
+	container.AddFactory(func() *memoryCache {
+		calls++
+		return new(memoryCache)
+	}, "cache")
+
+	cacheType, ok := container.TypeOf("cache")
+	if !ok || cacheType != reflect.TypeOf(new(memoryCache)) || calls != 0 {
+		t.Fail()
+	}
+}
+
+func TestString(t *testing.T) {
+	container := NewContainer()
+	container.Add("value", "b")
+	container.Add(3, "a")
+	container.Add(strings.Repeat("x", 100), "")
+	container.Add(container, "container")
+
+	expected := "summer.Container with 4 dependencies" +
+		"\n\ta int = 3" +
+		"\n\tb string = \"value\"" +
+		"\n\tcontainer *summer.Container = (this container)" +
+		"\n\t(unnamed) string = \"" + strings.Repeat("x", 36) + "..."
+	if dump := container.String(); dump != expected {
+		t.Log(dump)
+		t.Fail()
+	}
+}
+
+func TestStringTruncatesOnCharacterBoundaries(t *testing.T) {
+	container := NewContainer()
+	container.Add(strings.Repeat("é", 100), "")
+
+	expected := "summer.Container with 1 dependencies" +
+		"\n\t(unnamed) string = \"" + strings.Repeat("é", 36) + "..."
+	if dump := container.String(); dump != expected {
+		t.Log(dump)
+		t.Fail()
+	}
+}
+
+func TestWalkInjectionPointsRejectsNilPointers(t *testing.T) {
+	container := NewContainer()
+	err := container.WalkInjectionPoints((*dotServer)(nil), func(field string, tag string, resolvable bool) {
+		t.Fail()
+	})
+
+	if !errors.Is(err, ErrNotAStruct) {
+		t.Log(err)
+		t.Fail()
+	}
+}
@@ -0,0 +1,51 @@
+package summer
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+)
+
+// A dependency of interface type I that isn't built until it's first used,
+// see AddLazy.
+type Lazy[I any] struct {
+	once    sync.Once
+	factory func() I
+	value   I
+}
+
+// Returns the dependency, calling its factory if this is the first use
+func (l *Lazy[I]) Get() I {
+	l.once.Do(func() {
+		l.value = l.factory()
+		l.factory = nil
+	})
+	return l.value
+}
+
+// Adds a dependency of interface type I that's only built by calling
+// factory when it's first used, rather than when it's injected. This breaks
+// cycles between dependencies that only need each other once running.
+//
+// Go can't implement I at runtime, so proxy is asked to wrap a *Lazy[I] in
+// a small hand-written or generated type that implements I by forwarding
+// each method through Get. The proxy is added as I exactly as AddAs would,
+// so fields of type I receive it by name or by type:
+//
+//	type lazyMailer struct{ *summer.Lazy[Mailer] }
+//
+//	func (m lazyMailer) Send(to string) error { return m.Get().Send(to) }
+//
+//	summer.AddLazy(container, newSMTPMailer, func(l *summer.Lazy[Mailer]) Mailer {
+//		return lazyMailer{l}
+//	}, "mailer")
+//
+// AddLazy panics if I isn't an interface type.
+func AddLazy[I any](c *Container, factory func() I, proxy func(*Lazy[I]) I, name string) {
+	interfaceType := reflect.TypeOf((*I)(nil)).Elem()
+	if interfaceType.Kind() != reflect.Interface {
+		panic(fmt.Sprintf("Summer: Cannot lazily add %s, which isn't an interface", interfaceType))
+	}
+
+	AddAs[I](c, proxy(&Lazy[I]{factory: factory}), name)
+}
@@ -0,0 +1,49 @@
+package summer
+
+import "testing"
+
+type lazyHandler struct {
+	*Lazy[testHandler]
+}
+
+func (h lazyHandler) Handle() string {
+	return h.Get().Handle()
+}
+
+func newLazyHandler(l *Lazy[testHandler]) testHandler {
+	return lazyHandler{l}
+}
+
+func TestAddLazyBuildsOnFirstUse(t *testing.T) {
+	type simpleStruct struct {
+		Named testHandler `summer:"handler"`
+		Auto  testHandler `summer:",auto"`
+	}
+
+	calls := 0
+	container := NewContainer()
+	AddLazy(container, func() testHandler {
+		calls++
+		return namedHandler("a")
+	}, newLazyHandler, "handler")
+	s := new(simpleStruct)
+	err := container.InjectInto(s)
+	if err != nil || calls != 0 {
+		t.Fatal(err, calls)
+	}
+
+	if s.Named.Handle() != "a" || s.Auto.Handle() != "a" || calls != 1 {
+		t.Fail()
+	}
+}
+
+func TestAddLazyRejectsConcreteTypes(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Fail()
+		}
+	}()
+
+	AddLazy(NewContainer(), func() namedHandler { return "a" },
+		func(l *Lazy[namedHandler]) namedHandler { return "b" }, "handler")
+}
@@ -0,0 +1,188 @@
+package summer
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+)
+
+// Combines the registrations of several containers into a new container,
+// for wiring together modules that each configure their own container. The
+// containers are merged in order and are left untouched.
+//
+// An error is returned if two containers register different dependencies
+// under the same name, or different dependencies of the same type that are
+// both without a name, since one of them could then never be injected. Use
+// MergeLastWins to let later containers take precedence instead.
+//
+// Factories, aliases, streams and priorities are merged too, with later
+// containers taking precedence. So are scoped providers, strategies,
+// profiles and bindings, which are subject to the same conflicts: a scoped
+// provider for a name or unnamed type another container already has one
+// for, a strategy key already taken in its group, a profile registration
+// that conflicts with the same profile of another container, or a field
+// bound by another container. Settings such as StrictNames, the active
+// profile and the logger aren't carried over.
+func Merge(containers ...*Container) (*Container, error) {
+	return merge(containers, false)
+}
+
+// Like Merge, but a name or type registered by several containers refers to
+// the dependency from the last of them.
+func MergeLastWins(containers ...*Container) *Container {
+	merged, _ := merge(containers, true)
+	return merged
+}
+
+func merge(containers []*Container, lastWins bool) (*Container, error) {
+	merged := NewContainer()
+	for _, c := range containers {
+		if !lastWins {
+			if err := merged.checkMergeConflicts(c); err != nil {
+				return nil, err
+			}
+		}
+
+		for _, dependency := range c.dependencies {
+			if merged.indexOfDependency(dependency) < 0 {
+				merged.appendDependency(dependency)
+			}
+			if reflect.ValueOf(dependency).Comparable() && c.possibleInjectionSet.Contains(dependency) {
+				merged.possibleInjectionSet.Add(dependency)
+			}
+		}
+		for name, dependency := range c.dependenciesByName {
+			merged.dependenciesByName[name] = dependency
+		}
+		for t, dependency := range c.dependenciesByType {
+			merged.dependenciesByType[t] = dependency
+		}
+		for t, dependency := range c.dependenciesByInterface {
+			merged.dependenciesByInterface[t] = dependency
+		}
+		for target, priority := range c.priorities {
+			merged.priorities[target] = priority
+		}
+		for newName, existingName := range c.aliases {
+			merged.aliases[newName] = existingName
+		}
+		for name, s := range c.streams {
+			merged.streams[name] = s
+		}
+		for name, f := range c.factories {
+			merged.factories[name] = f
+		}
+		for t, f := range c.factoriesByType {
+			merged.factoriesByType[t] = f
+		}
+		for t, candidates := range c.weightedDependencies {
+			merged.weightedDependencies[t] = append(merged.weightedDependencies[t], candidates...)
+		}
+		for _, f := range c.scoped {
+			merged.addMergedScoped(f)
+		}
+		for group, strategies := range c.strategyGroups {
+			if merged.strategyGroups[group] == nil {
+				merged.strategyGroups[group] = make(map[string]interface{})
+			}
+			for key, impl := range strategies {
+				merged.strategyGroups[group][key] = impl
+			}
+		}
+		for profile, registrations := range c.profiles {
+			if existing, ok := merged.profiles[profile]; ok {
+				merged.profiles[profile] = MergeLastWins(existing, registrations)
+			} else {
+				merged.profiles[profile] = registrations.Clone()
+			}
+		}
+		for target, bindings := range c.bindings {
+			merged.bindings[target] = append(merged.bindings[target], bindings...)
+		}
+	}
+
+	return merged, nil
+}
+
+// Finds registrations in other that would replace different ones in c
+func (c *Container) checkMergeConflicts(other *Container) error {
+	for name, dependency := range other.dependenciesByName {
+		if existing, ok := c.dependenciesByName[name]; ok && !isSameDependency(existing, dependency) {
+			return errors.New(
+				fmt.Sprintf("Summer: Cannot merge containers, both register a dependency named %s", name))
+		}
+	}
+
+	for t, dependency := range other.dependenciesByType {
+		existing, ok := c.dependenciesByType[t]
+		if ok && !isSameDependency(existing, dependency) && !c.isNamed(existing) && !other.isNamed(dependency) {
+			return errors.New(
+				fmt.Sprintf("Summer: Cannot merge containers, both register an unnamed dependency of type %s", t))
+		}
+	}
+
+	for _, f := range other.scoped {
+		for _, existing := range c.scoped {
+			if existing != f && existing.name == f.name && (f.name != "" || existing.resultType() == f.resultType()) {
+				return errors.New(
+					fmt.Sprintf("Summer: Cannot merge containers, both register %s", f))
+			}
+		}
+	}
+
+	for group, strategies := range other.strategyGroups {
+		for key, impl := range strategies {
+			if existing, ok := c.strategyGroups[group][key]; ok && !isSameDependency(existing, impl) {
+				return errors.New(
+					fmt.Sprintf("Summer: Cannot merge containers, both register strategy %s in group %s", key, group))
+			}
+		}
+	}
+
+	for profile, registrations := range other.profiles {
+		if existing, ok := c.profiles[profile]; ok {
+			if err := existing.checkMergeConflicts(registrations); err != nil {
+				return errors.New(fmt.Sprintf("%v in profile %s", err, profile))
+			}
+		}
+	}
+
+	for target, bindings := range other.bindings {
+		for _, bound := range bindings {
+			for _, existing := range c.bindings[target] {
+				if existing.field == bound.field {
+					return errors.New(
+						fmt.Sprintf("Summer: Cannot merge containers, both bind %s's field %s",
+							reflect.TypeOf(target).Elem(), bound.field))
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+// Adds the scoped provider unless it's already there, replacing any other
+// for the same name or unnamed type
+func (c *Container) addMergedScoped(f *factory) {
+	for index, existing := range c.scoped {
+		if existing == f {
+			return
+		}
+		if existing.name == f.name && (f.name != "" || existing.resultType() == f.resultType()) {
+			c.scoped[index] = f
+			return
+		}
+	}
+	c.scoped = append(c.scoped, f)
+}
+
+// Whether the dependency is registered under any name
+func (c *Container) isNamed(dependency interface{}) bool {
+	for _, named := range c.dependenciesByName {
+		if isSameDependency(named, dependency) {
+			return true
+		}
+	}
+	return false
+}
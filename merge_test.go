@@ -0,0 +1,140 @@
+package summer
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+func TestMergeCombinesContainers(t *testing.T) {
+	type simpleStruct struct {
+		Database string      `summer:"database"`
+		Handler  testHandler `summer:"handler"`
+	}
+
+	storage := NewContainer()
+	storage.Add("postgres", "database")
+	web := NewContainer()
+	web.Add(namedHandler("a"), "handler")
+	s := new(simpleStruct)
+	web.Add(s, "")
+	merged, err := Merge(storage, web)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = merged.PerformInjections()
+
+	if err != nil || s.Database != "postgres" || s.Handler != namedHandler("a") || merged.Len() != 3 {
+		t.Log(err)
+		t.Fail()
+	}
+	if storage.Len() != 1 || web.Len() != 2 {
+		t.Fail()
+	}
+}
+
+func TestMergeRejectsConflictingNames(t *testing.T) {
+	first := NewContainer()
+	first.Add("postgres", "database")
+	second := NewContainer()
+	second.Add("mysql", "database")
+	_, err := Merge(first, second)
+
+	if err == nil {
+		t.Fail()
+	}
+}
+
+func TestMergeRejectsConflictingUnnamedTypes(t *testing.T) {
+	first := NewContainer()
+	first.Add(namedHandler("a"), "")
+	second := NewContainer()
+	second.Add(namedHandler("b"), "")
+	_, err := Merge(first, second)
+
+	if err == nil {
+		t.Fail()
+	}
+}
+
+func TestMergeLastWinsResolvesConflicts(t *testing.T) {
+	first := NewContainer()
+	first.Add("postgres", "database")
+	first.Add(namedHandler("a"), "")
+	second := NewContainer()
+	second.Add("mysql", "database")
+	second.Add(namedHandler("b"), "")
+	merged := MergeLastWins(first, second)
+
+	database, _ := merged.Get("database")
+	handler, _ := merged.GetOrType("", reflect.TypeOf(namedHandler("")))
+	if database != "mysql" || handler != namedHandler("b") {
+		t.Fail()
+	}
+}
+
+func TestMergeCombinesScopedStrategiesProfilesAndBindings(t *testing.T) {
+	type simpleStruct struct {
+		Transaction *scopedTransaction `summer:",auto"`
+		Parser      testHandler        `summer:"format,strategy=parsers"`
+		Mailer      string             `summer:"mailer"`
+		Port        int
+	}
+
+	storage := NewContainer()
+	storage.AddScoped(func() *scopedTransaction { return new(scopedTransaction) }, "")
+	storage.AddStrategy("parsers", "json", namedHandler("json parser"))
+	storage.AddToProfile("test", "fake", "mailer")
+	web := NewContainer()
+	web.Add("json", "format")
+	web.AddStrategy("parsers", "yaml", namedHandler("yaml parser"))
+	s := new(simpleStruct)
+	web.Bind(s, "Port", func(c *Container) interface{} { return 8080 })
+	merged, err := Merge(storage, web)
+	if err != nil {
+		t.Fatal(err)
+	}
+	merged.ActivateProfile("test")
+	err = merged.EnterScope().InjectInto(s)
+
+	if err != nil || s.Transaction == nil || s.Parser != namedHandler("json parser") ||
+		s.Mailer != "fake" || s.Port != 8080 {
+		t.Log(err, s)
+		t.Fail()
+	}
+}
+
+func TestMergeRejectsConflictingScopedStrategiesProfilesAndBindings(t *testing.T) {
+	type simpleStruct struct {
+		Port int
+	}
+	s := new(simpleStruct)
+	register := map[string]func(c *Container, value int){
+		"scoped": func(c *Container, value int) {
+			c.AddScoped(func() *scopedTransaction { return new(scopedTransaction) }, "transaction")
+		},
+		"strategy": func(c *Container, value int) {
+			c.AddStrategy("parsers", "json", namedHandler(fmt.Sprint(value)))
+		},
+		"profile": func(c *Container, value int) {
+			c.AddToProfile("test", value, "port")
+		},
+		"binding": func(c *Container, value int) {
+			c.Bind(s, "Port", func(c *Container) interface{} { return value })
+		},
+	}
+
+	for name, add := range register {
+		first, second := NewContainer(), NewContainer()
+		add(first, 1)
+		add(second, 2)
+		if _, err := Merge(first, second); err == nil {
+			t.Log(name)
+			t.Fail()
+		}
+		if merged := MergeLastWins(first, second); merged == nil {
+			t.Log(name)
+			t.Fail()
+		}
+	}
+}
@@ -0,0 +1,83 @@
+package summer
+
+import (
+	"reflect"
+	"sync"
+)
+
+// Notified of the container's registrations and injections, see
+// Container.Subscribe.
+type ContainerObserver interface {
+	// Called when a dependency is registered. The name is blank for
+	// dependencies that are only injectable by type.
+	OnAdd(name string, t reflect.Type)
+
+	// Called when a dependency of type dep has been injected into the named
+	// field of a struct of type target.
+	OnInject(target reflect.Type, field string, dep reflect.Type)
+}
+
+// How many notifications may wait for an observer before further ones are
+// dropped
+const observerQueueSize = 1024
+
+// An observer and the notifications queued for it
+type subscription struct {
+	observer ContainerObserver
+	events   chan func(observer ContainerObserver)
+	pending  sync.WaitGroup
+}
+
+// Registers an observer to be notified of every registration and injection
+// from now on, which lets metrics and instrumentation hook into the
+// container. Observers are called on a goroutine of their own, in the order
+// the events happened, so a slow observer never holds up the container. If
+// an observer falls too far behind, events are dropped rather than waited
+// for. A panicking observer is ignored rather than interrupting the
+// container.
+func (c *Container) Subscribe(observer ContainerObserver) {
+	s := &subscription{observer: observer, events: make(chan func(observer ContainerObserver), observerQueueSize)}
+	c.observers = append(c.observers, s)
+
+	go func() {
+		for event := range s.events {
+			notify(func() { event(s.observer) })
+			s.pending.Done()
+		}
+	}()
+}
+
+func (c *Container) notifyAdd(name string, t reflect.Type) {
+	c.publish(func(observer ContainerObserver) { observer.OnAdd(name, t) })
+}
+
+func (c *Container) notifyInject(p injectionPoint, dep reflect.Type) {
+	c.publish(func(observer ContainerObserver) { observer.OnInject(p.elementType, p.typeField.Name, dep) })
+}
+
+// Queues the event for every observer, without waiting for any of them
+func (c *Container) publish(event func(observer ContainerObserver)) {
+	for _, s := range c.observers {
+		s.pending.Add(1)
+		select {
+		case s.events <- event:
+		default:
+			s.pending.Done()
+		}
+	}
+}
+
+// Waits until every observer has been called with the events queued so far
+func (c *Container) flushObservers() {
+	for _, s := range c.observers {
+		s.pending.Wait()
+	}
+}
+
+// Calls fn, discarding any panic
+func notify(fn func()) {
+	defer func() {
+		recover()
+	}()
+	fn()
+}
@@ -0,0 +1,114 @@
+package summer
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+)
+
+type recordingObserver struct {
+	events []string
+}
+
+func (o *recordingObserver) OnAdd(name string, t reflect.Type) {
+	o.events = append(o.events, fmt.Sprintf("add %s %s", name, t))
+}
+
+func (o *recordingObserver) OnInject(target reflect.Type, field string, dep reflect.Type) {
+	o.events = append(o.events, fmt.Sprintf("inject %s.%s %s", target, field, dep))
+}
+
+type panickingObserver struct{}
+
+func (panickingObserver) OnAdd(name string, t reflect.Type) {
+	panic("add")
+}
+
+func (panickingObserver) OnInject(target reflect.Type, field string, dep reflect.Type) {
+	panic("inject")
+}
+
+func TestObserverIsNotified(t *testing.T) {
+	type observedStruct struct {
+		Name    string      `summer:"name"`
+		Handler testHandler `summer:"handler"`
+	}
+
+	observer := new(recordingObserver)
+	container := NewContainer()
+	container.Subscribe(observer)
+	container.Add("value", "name")
+	container.Add(namedHandler("a"), "handler")
+	err := container.InjectInto(new(observedStruct))
+	container.flushObservers()
+
+	expected := []string{
+		"add name string",
+		"add handler summer.namedHandler",
+		"inject summer.observedStruct.Name string",
+		"inject summer.observedStruct.Handler summer.namedHandler",
+	}
+	if err != nil || !reflect.DeepEqual(observer.events, expected) {
+		t.Log(err, observer.events)
+		t.Fail()
+	}
+}
+
+func TestPanickingObserverDoesNotInterruptInjection(t *testing.T) {
+	type observedStruct struct {
+		Name string `summer:"name"`
+	}
+
+	container := NewContainer()
+	container.Subscribe(panickingObserver{})
+	container.Add("value", "name")
+	s := new(observedStruct)
+	err := container.InjectInto(s)
+
+	if err != nil || s.Name != "value" {
+		t.Log(err)
+		t.Fail()
+	}
+}
+
+type blockingObserver struct {
+	release chan struct{}
+}
+
+func (o blockingObserver) OnAdd(name string, t reflect.Type) {
+	<-o.release
+}
+
+func (o blockingObserver) OnInject(target reflect.Type, field string, dep reflect.Type) {
+	<-o.release
+}
+
+func TestBlockingObserverDoesNotBlockInjection(t *testing.T) {
+	type observedStruct struct {
+		Name string `summer:"name"`
+	}
+
+	observer := blockingObserver{release: make(chan struct{})}
+	defer close(observer.release)
+	container := NewContainer()
+	container.Subscribe(observer)
+	done := make(chan error, 1)
+	go func() {
+		for i := 0; i < 2*observerQueueSize; i++ {
+			container.Add(i, "")
+		}
+		container.Add("value", "name")
+		done <- container.InjectInto(new(observedStruct))
+	}()
+
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Log(err)
+			t.Fail()
+		}
+	case <-time.After(time.Second):
+		t.Fatal("injection waited for the observer")
+	}
+}
@@ -0,0 +1,99 @@
+package summer
+
+import "reflect"
+
+// Adds a dependency exactly like AddE, except that it's only used while the
+// profile is active, see ActivateProfile. While active, a profile's
+// dependencies take precedence over those added without a profile under
+// the same name or type.
+func (c *Container) AddToProfile(profile string, target interface{}, name string) error {
+	if err := c.checkFrozen(); err != nil {
+		return err
+	}
+	p, ok := c.profiles[profile]
+	if !ok {
+		p = NewContainer()
+		p.StrictNames = c.StrictNames
+		c.profiles[profile] = p
+	}
+
+	return p.AddE(target, name)
+}
+
+// Makes the profile's dependencies available for injection and Get, in
+// place of those of any previously active profile. Dependencies added
+// without a profile are always available. Activating a profile nothing has
+// been added to, such as "", leaves only those dependencies.
+func (c *Container) ActivateProfile(profile string) {
+	c.activeProfile = profile
+}
+
+// The registrations of the active profile, or nil if there are none
+func (c *Container) activeRegistrations() *Container {
+	return c.profiles[c.activeProfile]
+}
+
+// Finds a dependency by its exact type, preferring the active profile's
+func (c *Container) lookupType(t reflect.Type) (interface{}, bool) {
+	if profile := c.activeRegistrations(); profile != nil {
+		if dependency, ok := profile.dependenciesByType[t]; ok {
+			return dependency, true
+		}
+	}
+
+	dependency, ok := c.dependenciesByType[t]
+	return dependency, ok
+}
+
+// Every unique dependency available, in the order they were added: those
+// added without a profile, then those of the active profile
+func (c *Container) activeDependencies() []interface{} {
+	profile := c.activeRegistrations()
+	if profile == nil {
+		return c.dependencies
+	}
+
+	dependencies := append([]interface{}(nil), c.dependencies...)
+	for _, dependency := range profile.dependencies {
+		if c.indexOfDependency(dependency) < 0 {
+			dependencies = append(dependencies, dependency)
+		}
+	}
+	return dependencies
+}
+
+// The available dependencies by name, the active profile's taking
+// precedence
+func (c *Container) activeNames() map[string]interface{} {
+	profile := c.activeRegistrations()
+	if profile == nil {
+		return c.dependenciesByName
+	}
+
+	names := make(map[string]interface{}, len(c.dependenciesByName)+len(profile.dependenciesByName))
+	for name, dependency := range c.dependenciesByName {
+		names[name] = dependency
+	}
+	for name, dependency := range profile.dependenciesByName {
+		names[name] = dependency
+	}
+	return names
+}
+
+// The available dependencies by type, the active profile's taking
+// precedence
+func (c *Container) activeTypes() map[reflect.Type]interface{} {
+	profile := c.activeRegistrations()
+	if profile == nil {
+		return c.dependenciesByType
+	}
+
+	types := make(map[reflect.Type]interface{}, len(c.dependenciesByType)+len(profile.dependenciesByType))
+	for t, dependency := range c.dependenciesByType {
+		types[t] = dependency
+	}
+	for t, dependency := range profile.dependenciesByType {
+		types[t] = dependency
+	}
+	return types
+}
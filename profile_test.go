@@ -0,0 +1,95 @@
+package summer
+
+import "testing"
+
+func TestActivateProfileSelectsImplementation(t *testing.T) {
+	type simpleStruct struct {
+		Mailer  string             `summer:"mailer"`
+		Handler testHandler        `summer:"handler"`
+		Hook    *hookTestingStruct `summer:",auto,orNew"`
+	}
+
+	container := NewContainer()
+	container.Add(namedHandler("default"), "handler")
+	container.AddToProfile("prod", "smtp", "mailer")
+	container.AddToProfile("test", "fake", "mailer")
+	container.AddToProfile("test", namedHandler("test"), "handler")
+	hook := new(hookTestingStruct)
+	container.AddToProfile("test", hook, "")
+
+	container.ActivateProfile("prod")
+	prod := new(simpleStruct)
+	prodErr := container.InjectInto(prod)
+	container.ActivateProfile("test")
+	test := new(simpleStruct)
+	testErr := container.InjectInto(test)
+
+	if prodErr != nil || prod.Hook == hook || prod.Mailer != "smtp" || prod.Handler != namedHandler("default") {
+		t.Log(prodErr, prod)
+		t.Fail()
+	}
+	if testErr != nil || test.Mailer != "fake" || test.Handler != namedHandler("test") || test.Hook != hook {
+		t.Log(testErr, test)
+		t.Fail()
+	}
+}
+
+func TestInactiveProfileIsNotAvailable(t *testing.T) {
+	container := NewContainer()
+	container.AddToProfile("prod", "smtp", "mailer")
+
+	if _, ok := container.Get("mailer"); ok {
+		t.Fail()
+	}
+	container.ActivateProfile("prod")
+	if mailer, _ := container.Get("mailer"); mailer != "smtp" {
+		t.Fail()
+	}
+	container.ActivateProfile("")
+	if _, ok := container.Get("mailer"); ok {
+		t.Fail()
+	}
+}
+
+func TestPerformInjectionsInjectsActiveProfile(t *testing.T) {
+	type simpleStruct struct {
+		Mailer string `summer:"mailer"`
+	}
+
+	container := NewContainer()
+	container.Add("smtp", "mailer")
+	active := new(simpleStruct)
+	inactive := new(simpleStruct)
+	container.AddToProfile("test", active, "")
+	container.AddToProfile("prod", inactive, "")
+	container.ActivateProfile("test")
+	err := container.PerformInjections()
+
+	if err != nil || active.Mailer != "smtp" || inactive.Mailer != "" {
+		t.Log(err)
+		t.Fail()
+	}
+}
+
+func TestActiveProfileTakesPartInSliceAndTypeInjection(t *testing.T) {
+	type simpleStruct struct {
+		Handlers []testHandler `summer:",auto"`
+		Cache    testCache     `summer:",auto,type=*summer.redisCache"`
+		Second   testHandler   `summer:"#2"`
+	}
+
+	container := NewContainer()
+	container.Add(namedHandler("default"), "")
+	container.AddToProfile("test", upperHandler("test"), "")
+	redis := new(redisCache)
+	container.AddToProfile("test", redis, "")
+	container.ActivateProfile("test")
+	s := new(simpleStruct)
+	err := container.InjectInto(s)
+
+	if err != nil || len(s.Handlers) != 2 || s.Cache != redis || s.Second != upperHandler("test") ||
+		container.Len() != 3 {
+		t.Log(err, s)
+		t.Fail()
+	}
+}
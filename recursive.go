@@ -0,0 +1,44 @@
+package summer
+
+import (
+	"reflect"
+)
+
+// Like InjectInto, but also injects into the structs reachable from target
+// through its exported pointer-to-struct fields, once target's own fields
+// have been injected, and so on down the graph. Each struct is injected
+// into at most once per call, however many paths lead to it, so shared
+// dependencies aren't overwritten and cycles terminate.
+func (c *Container) InjectIntoRecursive(target interface{}) error {
+	return c.injectRecursive(target, newInterfaceSet())
+}
+
+func (c *Container) injectRecursive(target interface{}, visited *interfaceSet) error {
+	// Pointers to interfaces are visited as the struct they hold, as
+	// InjectInto would inject it
+	target = unwrapInterfacePointer(target)
+	if !isPointerToStruct(target) || reflect.ValueOf(target).IsNil() {
+		// Leaves explaining what's wrong with target to InjectInto
+		return c.InjectInto(target)
+	}
+	if !visited.Add(target) {
+		return nil
+	}
+
+	if err := c.InjectInto(target); err != nil {
+		return err
+	}
+
+	element := reflect.ValueOf(target).Elem()
+	for index := 0; index < element.NumField(); index++ {
+		field := element.Field(index)
+		if !field.CanInterface() || field.IsZero() || !isPointerToStruct(field.Interface()) {
+			continue
+		}
+		if err := c.injectRecursive(field.Interface(), visited); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,81 @@
+package summer
+
+import (
+	"errors"
+	"testing"
+)
+
+type diamondTop struct {
+	Left  *diamondSide `summer:"left"`
+	Right *diamondSide `summer:"right"`
+}
+
+type diamondSide struct {
+	Bottom *diamondBottom
+}
+
+type diamondBottom struct {
+	Name       string `summer:"name"`
+	injections int
+}
+
+func (b *diamondBottom) PostInjectionCallback() {
+	b.injections++
+}
+
+func TestInjectIntoRecursiveInjectsSharedStructOnce(t *testing.T) {
+	bottom := new(diamondBottom)
+	container := NewContainer()
+	container.Add(&diamondSide{Bottom: bottom}, "left")
+	container.Add(&diamondSide{Bottom: bottom}, "right")
+	container.Add("bottom", "name")
+	top := new(diamondTop)
+	err := container.InjectIntoRecursive(top)
+
+	if err != nil || top.Left.Bottom.Name != "bottom" || bottom.injections != 1 {
+		t.Log(err, bottom)
+		t.Fail()
+	}
+}
+
+func TestInjectIntoRecursiveStopsAtCycles(t *testing.T) {
+	type node struct {
+		Next *node
+		Name string `summer:"name"`
+	}
+
+	container := NewContainer()
+	container.Add("node", "name")
+	first := new(node)
+	first.Next = &node{Next: first}
+	err := container.InjectIntoRecursive(first)
+
+	if err != nil || first.Name != "node" || first.Next.Name != "node" {
+		t.Log(err)
+		t.Fail()
+	}
+}
+
+func TestInjectIntoRecursiveThroughInterfacePointer(t *testing.T) {
+	bottom := new(diamondBottom)
+	container := NewContainer()
+	container.Add("bottom", "name")
+	var side interface{} = &diamondSide{Bottom: bottom}
+	err := container.InjectIntoRecursive(&side)
+
+	if err != nil || bottom.Name != "bottom" || bottom.injections != 1 {
+		t.Log(err, bottom)
+		t.Fail()
+	}
+}
+
+func TestInjectIntoRecursiveRejectsNonStructs(t *testing.T) {
+	container := NewContainer()
+	nilErr := container.InjectIntoRecursive((*diamondSide)(nil))
+	mapErr := container.InjectIntoRecursive(map[string][]int{})
+
+	if !errors.Is(nilErr, ErrNotAStruct) || !errors.Is(mapErr, ErrNotAStruct) {
+		t.Log(nilErr, mapErr)
+		t.Fail()
+	}
+}
@@ -0,0 +1,46 @@
+package summer
+
+import "time"
+
+// Source of time for the container, replaceable in tests.
+type Clock interface {
+	Now() time.Time
+	Sleep(d time.Duration)
+}
+
+type realClock struct{}
+
+func (realClock) Now() time.Time {
+	return time.Now()
+}
+
+func (realClock) Sleep(d time.Duration) {
+	time.Sleep(d)
+}
+
+// Replaces the clock the container uses to wait and measure time, which is
+// otherwise the system clock.
+func (c *Container) SetClock(clock Clock) {
+	c.clock = clock
+}
+
+// Runs PerformInjections up to attempts times, waiting backoff between each
+// attempt, until one succeeds. This is meant for factories and providers
+// that can fail transiently, since failed constructions are retried on the
+// next attempt. Repeating an injection is harmless, as dependencies that
+// were already injected are simply injected again.
+//
+// The error from the final attempt is returned if none succeed.
+func (c *Container) PerformInjectionsRetry(attempts int, backoff time.Duration) error {
+	var err error
+	for attempt := 0; attempt < attempts; attempt++ {
+		if attempt > 0 {
+			c.clock.Sleep(backoff)
+		}
+		if err = c.PerformInjections(); err == nil {
+			return nil
+		}
+	}
+
+	return err
+}
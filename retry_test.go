@@ -0,0 +1,64 @@
+package summer
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+type fakeClock struct {
+	now    time.Time
+	sleeps []time.Duration
+}
+
+func (c *fakeClock) Now() time.Time {
+	return c.now
+}
+
+func (c *fakeClock) Sleep(d time.Duration) {
+	c.sleeps = append(c.sleeps, d)
+	c.now = c.now.Add(d)
+}
+
+func TestRetriesFlakyFactory(t *testing.T) {
+	type simpleStruct struct {
+		Connection string `summer:"Connection"`
+	}
+	clock := new(fakeClock)
+	calls := 0
+
+	container := NewContainer()
+	container.SetClock(clock)
+	container.AddFactory(func() (string, error) {
+		calls++
+		if calls == 1 {
+			return "", errors.New("connection refused")
+		}
+		return "connected", nil
+	}, "Connection")
+	s := new(simpleStruct)
+	container.Add(s, "")
+	err := container.PerformInjectionsRetry(3, time.Second)
+
+	if err != nil || s.Connection != "connected" || calls != 2 ||
+		len(clock.sleeps) != 1 || clock.sleeps[0] != time.Second {
+		t.Log(err)
+		t.Fail()
+	}
+}
+
+func TestRetryGivesUp(t *testing.T) {
+	clock := new(fakeClock)
+
+	container := NewContainer()
+	container.SetClock(clock)
+	container.AddFactory(func() (string, error) {
+		return "", errors.New("connection refused")
+	}, "Connection")
+	err := container.PerformInjectionsRetry(3, time.Second)
+
+	if err == nil || len(clock.sleeps) != 2 {
+		t.Log(err)
+		t.Fail()
+	}
+}
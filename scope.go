@@ -0,0 +1,65 @@
+package summer
+
+// Registers a provider whose result is constructed once per scope rather
+// than once per container, such as a database transaction for each request.
+// Scoped providers take the same forms as providers, but they're only
+// available within the containers returned by EnterScope; the container
+// they're added to never constructs them itself.
+//
+// An error is returned if provider doesn't have one of the supported
+// signatures.
+func (c *Container) AddScoped(provider interface{}, name string) error {
+	if err := c.checkFrozen(); err != nil {
+		return err
+	}
+	f, err := newFactory(provider, kindScoped, name)
+	if err != nil {
+		return err
+	}
+
+	c.scoped = append(c.scoped, f)
+	return nil
+}
+
+// Returns a child container for a single scope, such as a request, in which
+// each scoped provider constructs its own instance the first time it's
+// needed. Everything else is shared with c: singletons already constructed
+// are the same instances, and factories and providers still pending are
+// constructed by c, so every scope shares their results too.
+//
+// Anything added to the scope stays in the scope. PerformInjections on the
+// scope only injects into structs added to the scope, leaving c's structs
+// injected with c's dependencies.
+func (c *Container) EnterScope() *Container {
+	scope := c.Clone()
+	scope.parent = c
+	scope.possibleInjectionSet = newInterfaceSet()
+	scope.priorities = make(map[interface{}]int)
+
+	for _, f := range c.scoped {
+		if f.name != "" {
+			scope.factories[f.name] = f
+		}
+		scope.factoriesByType[f.resultType()] = f
+	}
+
+	return scope
+}
+
+// Obtains a singleton from the parent container, constructing it there if
+// it's still pending, so that it's shared by every scope
+func (c *Container) constructInParent(f *factory, trace *resolutionTrace) (interface{}, bool) {
+	var dependency interface{}
+	var ok bool
+	if f.name != "" {
+		dependency, ok = c.parent.resolveName(f.name, trace)
+	} else {
+		dependency, ok = c.parent.resolveType(f.resultType(), trace)
+	}
+	if !ok {
+		return nil, false
+	}
+
+	c.replaceFactory(f, dependency)
+	return dependency, true
+}
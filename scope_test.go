@@ -0,0 +1,78 @@
+package summer
+
+import (
+	"testing"
+)
+
+type scopedTransaction struct {
+	DB *providedDB
+}
+
+func TestScopesGetDistinctScopedInstances(t *testing.T) {
+	type handler struct {
+		Transaction *scopedTransaction `summer:",auto"`
+		DB          *providedDB        `summer:",auto"`
+	}
+	constructions := 0
+
+	container := NewContainer()
+	container.AddFactory(func() *providedDB {
+		constructions++
+		return new(providedDB)
+	}, "")
+	container.AddScoped(func(db *providedDB) *scopedTransaction {
+		return &scopedTransaction{DB: db}
+	}, "")
+	first, second := container.EnterScope(), container.EnterScope()
+	h1, h2 := new(handler), new(handler)
+	err1 := first.InjectInto(h1)
+	err2 := second.InjectInto(h2)
+
+	if err1 != nil || err2 != nil || h1.Transaction == h2.Transaction ||
+		h1.DB != h2.DB || h1.Transaction.DB != h1.DB || constructions != 1 {
+		t.Log(err1, err2, constructions)
+		t.Fail()
+	}
+}
+
+func TestScopedInstanceSharedWithinScope(t *testing.T) {
+	type handler struct {
+		Transaction *scopedTransaction `summer:"transaction"`
+	}
+
+	container := NewContainer()
+	container.AddScoped(func() *scopedTransaction {
+		return new(scopedTransaction)
+	}, "transaction")
+	scope := container.EnterScope()
+	h1, h2 := new(handler), new(handler)
+	scope.InjectInto(h1)
+	err := scope.InjectInto(h2)
+	_, inParent := container.Get("transaction")
+
+	if err != nil || h1.Transaction == nil || h1.Transaction != h2.Transaction || inParent {
+		t.Log(err)
+		t.Fail()
+	}
+}
+
+func TestScopePerformInjectionsLeavesParentStructs(t *testing.T) {
+	type handler struct {
+		Transaction *scopedTransaction `summer:"transaction"`
+	}
+
+	container := NewContainer()
+	container.AddScoped(func() *scopedTransaction {
+		return new(scopedTransaction)
+	}, "transaction")
+	container.Add(new(handler), "")
+	scope := container.EnterScope()
+	h := new(handler)
+	scope.Add(h, "")
+	err := scope.PerformInjections()
+
+	if err != nil || h.Transaction == nil {
+		t.Log(err)
+		t.Fail()
+	}
+}
@@ -0,0 +1,30 @@
+package summer
+
+import "time"
+
+// Counts of the work a container has done, see Container.Stats
+type ContainerStats struct {
+	Injections    int           // Structs injected into, whether or not it succeeded
+	FieldsSet     int           // Fields set, including through setters
+	HooksRun      int           // Post injection hooks called
+	InjectionTime time.Duration // Total time spent injecting, excluding hooks
+}
+
+// Returns the counts collected since the container was created or
+// ResetStats was last called. Nothing is counted unless CollectStats is set,
+// which keeps the cost of injection down when the counts aren't wanted.
+// Time is measured with the container's clock, see SetClock.
+func (c *Container) Stats() ContainerStats {
+	return c.stats
+}
+
+// Sets every count returned by Stats back to zero.
+func (c *Container) ResetStats() {
+	c.stats = ContainerStats{}
+}
+
+// Counts an injection into a struct that began at start
+func (c *Container) recordInjection(start time.Time) {
+	c.stats.Injections++
+	c.stats.InjectionTime += c.clock.Now().Sub(start)
+}
@@ -0,0 +1,69 @@
+package summer
+
+import (
+	"testing"
+	"time"
+)
+
+type statsTestingStruct struct {
+	hookTestingStruct
+	Name string `summer:"name"`
+}
+
+func TestStatsCountInjections(t *testing.T) {
+	container := NewContainer()
+	container.CollectStats = true
+	container.Add("summer", "name")
+	container.Add(new(statsTestingStruct), "")
+	// Tagless, so its hook runs without injecting into it
+	container.Add(&hookTestingStruct{}, "")
+	err := container.PerformInjections()
+
+	stats := container.Stats()
+	if err != nil || stats.Injections != 1 || stats.FieldsSet != 1 || stats.HooksRun != 2 {
+		t.Log(err, stats)
+		t.Fail()
+	}
+
+	container.ResetStats()
+	if container.Stats() != (ContainerStats{}) {
+		t.Fail()
+	}
+}
+
+func TestStatsMeasureInjectionTime(t *testing.T) {
+	type simpleStruct struct {
+		Name string `summer:"name"`
+	}
+	clock := new(fakeClock)
+
+	container := NewContainer()
+	container.CollectStats = true
+	container.SetClock(clock)
+	container.Use(func(p InjectionContext, value interface{}) (interface{}, error) {
+		clock.Sleep(time.Second)
+		return value, nil
+	})
+	container.Add("summer", "name")
+	container.InjectInto(new(simpleStruct))
+	container.InjectInto(new(simpleStruct))
+
+	if container.Stats().InjectionTime != 2*time.Second {
+		t.Log(container.Stats())
+		t.Fail()
+	}
+}
+
+func TestStatsNotCollectedByDefault(t *testing.T) {
+	type simpleStruct struct {
+		Name string `summer:"name"`
+	}
+
+	container := NewContainer()
+	container.Add("summer", "name")
+	container.InjectInto(new(simpleStruct))
+
+	if container.Stats() != (ContainerStats{}) {
+		t.Fail()
+	}
+}
@@ -0,0 +1,147 @@
+package summer
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+	"reflect"
+)
+
+// Names accepted by the strategy tag option
+const (
+	strategyWeighted = "weighted"
+)
+
+// One of several equivalent dependencies, chosen in proportion to its weight
+type weightedDependency struct {
+	dependency interface{}
+	weight     int
+}
+
+// Adds a dependency exactly like AddE, additionally making it a candidate for
+// fields tagged with `summer:",auto,strategy=weighted"`. Each injection into
+// such a field picks one of the candidates of the field's type at random,
+// with a probability proportional to its weight. Weights must be positive.
+func (c *Container) AddWithWeight(target interface{}, name string, weight int) error {
+	if weight <= 0 {
+		return errors.New(
+			fmt.Sprintf("Summer: Weight for %T must be positive, got %d", target, weight))
+	}
+
+	if err := c.AddE(target, name); err != nil {
+		return err
+	}
+
+	t := reflect.TypeOf(target)
+	c.weightedDependencies[t] = append(c.weightedDependencies[t],
+		weightedDependency{dependency: target, weight: weight})
+
+	return nil
+}
+
+// Adds impl to the named group of strategies under key, for injection into
+// fields tagged with `summer:"keyName,strategy=group"`. Such fields receive
+// the strategy in the group whose key is the string dependency called
+// keyName, so which implementation is used can come from configuration. When
+// the tag has no name, as in `summer:",strategy=group"`, the key is the
+// string dependency named after the group itself.
+//
+// Strategies are only available through their group; add them separately if
+// they're also needed by name or type. A strategy with the same group and key
+// as an earlier one replaces it.
+func (c *Container) AddStrategy(group, key string, impl interface{}) {
+	if err := c.checkFrozen(); err != nil {
+		panic(err)
+	}
+	if c.strategyGroups[group] == nil {
+		c.strategyGroups[group] = make(map[string]interface{})
+	}
+	c.strategyGroups[group][key] = impl
+}
+
+// Replaces the container's source of randomness, which is otherwise seeded
+// from the current time. Mostly useful for deterministic tests.
+func (c *Container) SetRand(r *rand.Rand) {
+	c.rand = r
+}
+
+func (c *Container) performStrategyInjection(p injectionPoint, strategy string) error {
+	switch strategy {
+	case strategyWeighted:
+		return c.performWeightedInjection(p)
+	}
+	if _, ok := c.strategyGroups[strategy]; ok {
+		return c.performGroupInjection(p, strategy)
+	}
+
+	return errors.New(
+		fmt.Sprintf("Summer: Unknown strategy %s for %s's field %s",
+			strategy, p.elementType, p.typeField.Name))
+}
+
+func (c *Container) performWeightedInjection(p injectionPoint) error {
+	candidates := c.weightedDependencies[p.typeField.Type]
+	if len(candidates) == 0 {
+		return fmt.Errorf("Summer: Missing weighted dependency %s's field %s"+
+			", searched for type %s: %w",
+			p.elementType, p.typeField.Name, p.typeField.Type, ErrMissingDependency)
+	}
+
+	total := 0
+	for _, candidate := range candidates {
+		total += candidate.weight
+	}
+
+	choice := c.rand.Intn(total)
+	for _, candidate := range candidates {
+		if choice < candidate.weight {
+			c.markUsed(candidate.dependency)
+			return c.setField(p, reflect.ValueOf(candidate.dependency))
+		}
+		choice -= candidate.weight
+	}
+
+	return nil
+}
+
+func (c *Container) performGroupInjection(p injectionPoint, group string) error {
+	impl, err := c.selectStrategy(p, group)
+	if err != nil {
+		return err
+	}
+
+	value, ok := adaptDependency(impl, p.typeField.Type)
+	if !ok {
+		return newTypeMismatchError(p, impl)
+	}
+	return c.setField(p, value)
+}
+
+// Finds the strategy in the group whose key is given by the dependency
+// named in the field's tag, or failing that, named after the group
+func (c *Container) selectStrategy(p injectionPoint, group string) (interface{}, error) {
+	keyName := p.tag.dependencyName
+	if keyName == "" {
+		keyName = group
+	}
+
+	key, ok := c.Get(keyName)
+	if !ok {
+		return nil, fmt.Errorf("Summer: Missing strategy key %s for %s's field %s: %w",
+			keyName, p.elementType, p.typeField.Name, ErrMissingDependency)
+	}
+	keyString, ok := key.(string)
+	if !ok {
+		return nil, errors.New(
+			fmt.Sprintf("Summer: Strategy key %s for %s's field %s is a %T, not a string",
+				keyName, p.elementType, p.typeField.Name, key))
+	}
+
+	impl, ok := c.strategyGroups[group][keyString]
+	if !ok {
+		return nil, fmt.Errorf("Summer: Missing strategy %s in group %s for %s's field %s: %w",
+			keyString, group, p.elementType, p.typeField.Name, ErrMissingDependency)
+	}
+
+	return impl, nil
+}
@@ -0,0 +1,96 @@
+package summer
+
+import (
+	"errors"
+	"math/rand"
+	"testing"
+)
+
+type weightedBackend struct {
+	Address string
+}
+
+func TestWeightedStrategyFollowsWeights(t *testing.T) {
+	type simpleStruct struct {
+		Backend *weightedBackend `summer:",auto,strategy=weighted"`
+	}
+	light := &weightedBackend{Address: "light"}
+	heavy := &weightedBackend{Address: "heavy"}
+
+	container := NewContainer()
+	container.SetRand(rand.New(rand.NewSource(1)))
+	container.AddWithWeight(light, "", 1)
+	container.AddWithWeight(heavy, "", 3)
+
+	counts := make(map[*weightedBackend]int)
+	for i := 0; i < 4000; i++ {
+		s := new(simpleStruct)
+		if err := container.InjectInto(s); err != nil {
+			t.Fatal(err)
+		}
+		counts[s.Backend]++
+	}
+
+	if counts[light]+counts[heavy] != 4000 ||
+		counts[light] < 900 || counts[light] > 1100 {
+		t.Log(counts[light], counts[heavy])
+		t.Fail()
+	}
+}
+
+func TestWeightedStrategyRejectsInvalidWeight(t *testing.T) {
+	container := NewContainer()
+
+	if container.AddWithWeight(new(weightedBackend), "", 0) == nil {
+		t.Fail()
+	}
+}
+
+func TestThrowsErrorForUnknownStrategy(t *testing.T) {
+	type simpleStruct struct {
+		Backend *weightedBackend `summer:",auto,strategy=fastest"`
+	}
+
+	container := NewContainer()
+	container.Add(new(weightedBackend), "")
+
+	if container.InjectInto(new(simpleStruct)) == nil {
+		t.Fail()
+	}
+}
+
+func TestGroupStrategySelectedByKey(t *testing.T) {
+	type simpleStruct struct {
+		Parser  testHandler `summer:"format,strategy=parsers"`
+		Default testHandler `summer:",strategy=parsers"`
+	}
+
+	container := NewContainer()
+	container.AddStrategy("parsers", "json", namedHandler("json parser"))
+	container.AddStrategy("parsers", "yaml", namedHandler("yaml parser"))
+	container.Add("yaml", "format")
+	container.Add("json", "parsers")
+	s := new(simpleStruct)
+	err := container.InjectInto(s)
+
+	if err != nil || s.Parser.Handle() != "yaml parser" || s.Default.Handle() != "json parser" {
+		t.Log(err, s)
+		t.Fail()
+	}
+}
+
+func TestGroupStrategyMissingKey(t *testing.T) {
+	type simpleStruct struct {
+		Parser testHandler `summer:"format,strategy=parsers"`
+	}
+
+	container := NewContainer()
+	container.AddStrategy("parsers", "json", namedHandler("json parser"))
+	container.Add("toml", "format")
+	err := container.InjectInto(new(simpleStruct))
+
+	if !errors.Is(err, ErrMissingDependency) {
+		t.Log(err)
+		t.Fail()
+	}
+}
@@ -0,0 +1,103 @@
+package summer
+
+import "sync"
+
+// A named registration whose value is supplied over a channel. The most
+// recently received value is the current registration.
+type stream struct {
+	mutex    sync.RWMutex
+	latest   interface{}
+	received bool
+}
+
+func (s *stream) set(value interface{}) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.latest = value
+	s.received = true
+}
+
+// Returns the latest value, or false if nothing has been received yet
+func (s *stream) get() (interface{}, bool) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	return s.latest, s.received
+}
+
+// Registers a named dependency whose value is supplied asynchronously, for
+// example by service discovery. Every value received on ch replaces the
+// current registration for name; until the first value arrives the name is
+// treated as missing. Streamed values are only available by name and are
+// never used for automatic injection by type.
+//
+// If EnableAutoReinject has been called, every struct in the container with
+// a field tagged for name is updated as new values arrive, exactly as
+// InjectInto would have set it. Values that don't suit a field are reported
+// to the logger rather than set.
+//
+// Updates are applied on a separate goroutine, at any time after a value is
+// sent. Fields that may be reinjected must only be read inside Synchronize,
+// and each value is set independently, so a struct with several streamed
+// fields may briefly hold a mix of old and new values. Get always returns
+// the latest value received and needs no synchronization.
+func (c *Container) AddStream(name string, ch <-chan interface{}) {
+	if err := c.checkFrozen(); err != nil {
+		panic(err)
+	}
+	s := new(stream)
+	c.streams[name] = s
+
+	go func() {
+		for value := range ch {
+			s.set(value)
+			if c.autoReinject.Load() {
+				c.reinjectNamed(name, value)
+			}
+		}
+	}()
+}
+
+// Causes values received by AddStream to be injected into every struct in
+// the container that depends on the streamed name.
+func (c *Container) EnableAutoReinject() {
+	c.autoReinject.Store(true)
+}
+
+// Injects value into every settable field tagged with the given dependency
+// name, exactly as InjectInto would have. Fields that can't take the value
+// are left as they are and reported to the logger, if any.
+func (c *Container) reinjectNamed(name string, value interface{}) {
+	var points []injectionPoint
+	c.mutex.Lock()
+	c.possibleInjectionSet.EachElement(func(key interface{}) {
+		iterateTaggedFields(key, func(p injectionPoint) error {
+			p.tag = c.fieldTagFor(p.typeField)
+			if p.tag == nil || p.tag.autoInject || p.tag.dependencyName != name || !p.isSettable() {
+				return nil
+			}
+			if p.tag.ifNil && isNillable(p.typeField.Type) && !p.field.IsNil() {
+				return nil
+			}
+			points = append(points, p)
+			return nil
+		})
+	})
+	c.mutex.Unlock()
+
+	for _, p := range points {
+		err := c.injectDependency(p, name, value)
+		c.logInjection(p, "by name "+name, err)
+	}
+}
+
+// Calls fn while no streamed value is being reinjected, so fn can safely
+// read fields that reinjection sets, see AddStream. fn mustn't add to or
+// inject with the container.
+func (c *Container) Synchronize(fn func()) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	fn()
+}
@@ -0,0 +1,76 @@
+package summer
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+// Polls the container until the named dependency holds the expected value
+func waitForValue(container *Container, name string, expected interface{}) bool {
+	deadline := time.Now().Add(time.Second)
+	for time.Now().Before(deadline) {
+		if value, ok := container.Get(name); ok && value == expected {
+			return true
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	return false
+}
+
+func TestAddStreamUpdatesRegistration(t *testing.T) {
+	ch := make(chan interface{})
+	container := NewContainer()
+	container.AddStream("Backend", ch)
+
+	if _, ok := container.Get("Backend"); ok {
+		t.Fail()
+	}
+
+	ch <- "10.0.0.1"
+	if !waitForValue(container, "Backend", "10.0.0.1") {
+		t.Fail()
+	}
+
+	ch <- "10.0.0.2"
+	if !waitForValue(container, "Backend", "10.0.0.2") {
+		t.Fail()
+	}
+	close(ch)
+}
+
+func TestAutoReinjectUpdatesTaggedFields(t *testing.T) {
+	type simpleStruct struct {
+		Backend string `summer:"Backend"`
+	}
+	read := func(container *Container, s *simpleStruct) (backend string) {
+		container.Synchronize(func() { backend = s.Backend })
+		return backend
+	}
+
+	ch := make(chan interface{})
+	logger := new(capturingLogger)
+	container := NewContainer()
+	container.SetLogger(logger)
+	container.Use(func(p InjectionContext, value interface{}) (interface{}, error) {
+		return strings.ToUpper(value.(string)), nil
+	})
+	s := new(simpleStruct)
+	container.Add(s, "")
+	container.AddStream("Backend", ch)
+	container.EnableAutoReinject()
+
+	// A value of the wrong type is reported rather than set
+	ch <- 10
+	ch <- "primary"
+	deadline := time.Now().Add(time.Second)
+	for read(container, s) != "PRIMARY" && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	close(ch)
+
+	if read(container, s) != "PRIMARY" {
+		t.Fail()
+	}
+}
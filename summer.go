@@ -3,15 +3,46 @@
 package summer
 
 import (
+	"context"
 	"errors"
 	"fmt"
+	"math/rand"
 	"reflect"
+	"sort"
+	"strconv"
 	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
 )
 
 const (
 	summerTag     = "summer"
 	tagAutoInject = "auto"
+	tagStrategy   = "strategy="
+	tagOrNew      = "orNew"
+	tagType       = "type="
+	tagInject     = "inject"
+	tagSelf       = "self"
+	tagSetter     = "setter="
+	tagIfNil      = "ifNil"
+	tagOrder      = "order="
+)
+
+var (
+	// Wrapped by the errors for fields whose dependency can't be found
+	ErrMissingDependency = errors.New("missing dependency")
+
+	// Wrapped by the errors for targets that aren't a pointer to a struct
+	ErrNotAStruct = errors.New("not a pointer to a struct")
+
+	// Wrapped by the errors for changes to a frozen container, see
+	// Container.Freeze
+	ErrFrozen = errors.New("container is frozen")
+
+	// Returned for auto injection misses when AutoMissIsNil is set, and
+	// never seen by callers
+	errLeftUnset = errors.New("left unset")
 )
 
 type PostInjector interface {
@@ -20,10 +51,124 @@ type PostInjector interface {
 	PostInjectionCallback()
 }
 
+// Receives details of the injection process, see Container.SetLogger.
+type Logger interface {
+	Logf(format string, args ...interface{})
+}
+
+// Orders dependencies injected into a slice field: lower priorities come
+// first. Dependencies without a priority count as zero.
+type Prioritized interface {
+	Priority() int
+}
+
+type PostInjectorE interface {
+	// Like PostInjector, but a returned error fails the injection. A
+	// type can't implement both, as the methods share a name.
+	PostInjectionCallback() error
+}
+
+type ContextPostInjector interface {
+	// Like PostInjector, but receives the context passed to
+	// PerformInjectionsCtx. Preferred over PostInjector when a target
+	// implements both.
+	PostInjectionCallbackCtx(ctx context.Context)
+}
+
+// Decides which of several dependencies of the same type is auto injected,
+// see Container.OnDuplicateType. Dependencies added by name are always
+// available by that name, whatever the policy.
+type DuplicateTypePolicy int
+
+const (
+	// The last dependency added of a type is auto injected
+	DuplicateLastWins DuplicateTypePolicy = iota
+
+	// The first dependency added of a type is auto injected
+	DuplicateFirstWins
+
+	// Adding a second dependency of a type is an error
+	DuplicateError
+)
+
 // The dependency injection container, where your dependencies can be
 // named and then injected into your service's structs. This should always
 // be instantiated with NewContainer.
 type Container struct {
+	// Whether adding a dependency under a name that's already taken is an
+	// error. By default, the last dependency added under a name wins.
+	StrictNames bool
+
+	// What happens when a dependency is added with the same type as an
+	// earlier one, see DuplicateTypePolicy. By default the last one added is
+	// injected by type.
+	OnDuplicateType DuplicateTypePolicy
+
+	// How long each factory and provider may take to construct its
+	// dependency before giving up, see ConstructTimeoutError. Zero means no
+	// limit.
+	ConstructTimeout time.Duration
+
+	// Whether auto injecting a field is an error when several dependencies
+	// of its type were added, rather than using the last. Fields that name
+	// a dependency to try first aren't affected when it's found, and
+	// neither are containers with another OnDuplicateType policy.
+	StrictAuto bool
+
+	// Whether auto injected arrays must be filled exactly. By default,
+	// arrays with more elements than matching dependencies are partially
+	// filled.
+	StrictArrays bool
+
+	// Whether adding a struct with unrecognised tag options is an error,
+	// see ValidateTags.
+	StrictTags bool
+
+	// Whether ${VAR} and $VAR references in named string dependencies are
+	// replaced by the values of environment variables when injected. Unset
+	// variables expand to the empty string, unless StrictEnv is also set,
+	// in which case they're an error.
+	ExpandEnv bool
+	StrictEnv bool
+
+	// Whether named string dependencies are parsed when injected into fields
+	// of type time.Duration, bool, or any integer or floating point type.
+	ConvertPrimitives bool
+
+	// Whether fields can be injected with a dependency of a different defined
+	// type with the same underlying type, such as a string for a field of
+	// type ID declared as `type ID string`. Auto injection only falls back to
+	// this when no dependency has the field's exact type, and then picks the
+	// last matching dependency added. Beware that this can easily match more
+	// than intended: every string dependency can fill every string-like field.
+	// Type aliases, declared as `type ID = string`, are the same type and
+	// need no such matching.
+	MatchByUnderlyingType bool
+
+	// Whether exported fields without a summer tag are injected with the
+	// dependency named after the field, as if tagged `summer:","`. Untagged
+	// fields whose name isn't registered are left alone.
+	InjectUntaggedByName bool
+
+	// Whether a nil dependency can be added by name, in which case fields
+	// injected with it are set to their zero value. By default, adding nil
+	// is an error, as it's more often a mistake than not.
+	AllowNil bool
+
+	// Whether auto injected fields are left as they are, rather than failing
+	// the injection, when no dependency of their type was added. A warning
+	// is logged for each, see SetLogger. Fields whose factory or provider
+	// fails are still an error.
+	AutoMissIsNil bool
+
+	// Whether each field set is recorded, see History. Only the most recent
+	// HistoryLimit events are kept, or DefaultHistoryLimit if it's zero.
+	TrackHistory bool
+	HistoryLimit int
+
+	// Whether injections are counted and timed, see Stats
+	CollectStats bool
+
 	// Holds references to all of your dependencies
 	// indexed by name. Used for injection by specific name.
 	dependenciesByName map[string]interface{}
@@ -32,15 +177,117 @@ type Container struct {
 	// indexed by type. Used for auto injection by type.
 	dependenciesByType map[reflect.Type]interface{}
 
+	// Dependencies indexed by the interface they were added as, see AddAs
+	dependenciesByInterface map[reflect.Type]interface{}
+
 	// Set of references to each dependency
 	possibleInjectionSet *interfaceSet
+
+	// Every unique dependency, in the order they were added, and the
+	// position of each comparable one among them
+	dependencies    []interface{}
+	dependencyIndex map[interface{}]int
+
+	// Injection order of structs, see AddWithPriority
+	priorities map[interface{}]int
+
+	// Alternative names for dependencies, see Alias
+	aliases map[string]string
+
+	// Named dependencies supplied over channels, see AddStream
+	streams map[string]*stream
+
+	// Dependencies that are yet to be constructed, see AddFactory and
+	// AddProvider
+	factories       map[string]*factory
+	factoriesByType map[reflect.Type]*factory
+
+	// Constructed afresh in each scope, see AddScoped
+	scoped []*factory
+
+	// The container this one is a scope of, which constructs its
+	// singletons, see EnterScope
+	parent *Container
+
+	// Whether streamed values are pushed into dependent structs
+	autoReinject atomic.Bool
+
+	// Guards registrations and field writes against streamed values being
+	// reinjected on their own goroutine, see AddStream
+	mutex sync.Mutex
+
+	// Candidates for injection with the weighted strategy, see AddWithWeight
+	weightedDependencies map[reflect.Type][]weightedDependency
+
+	// Implementations chosen between by key, by group, see AddStrategy
+	strategyGroups map[string]map[string]interface{}
+
+	// Source of randomness for strategies that need it
+	rand *rand.Rand
+
+	// Receives spans for injections and hooks when set
+	tracer Tracer
+
+	// Used to wait between retries, see PerformInjectionsRetry
+	clock Clock
+
+	// Decides which dependencies suit an auto injected field when none has
+	// its exact type, see SetTypeMatcher
+	typeMatcher func(field reflect.Type, candidate reflect.Type) bool
+
+	// Receives a line for every field considered for injection when set
+	logger Logger
+
+	// Called after every successful PerformInjections, see OnReady
+	readyCallbacks []func()
+
+	// Called with every value about to be injected, see Use
+	interceptors []Interceptor
+
+	// Notified of registrations and injections, see Subscribe
+	observers []*subscription
+
+	// Fields set by functions rather than tags, see Bind
+	bindings map[interface{}][]binding
+
+	// Dependencies only used while their profile is active, see
+	// AddToProfile
+	profiles      map[string]*Container
+	activeProfile string
+
+	// Dependencies injected since PerformInjections last began, see
+	// UnusedDependencies
+	used *interfaceSet
+
+	// Counted while CollectStats is set
+	stats ContainerStats
+
+	// Recorded while TrackHistory is set, oldest first
+	history []InjectionEvent
+
+	// Whether registrations can no longer change, see Freeze
+	frozen bool
 }
 
 func NewContainer() *Container {
 	return &Container{
-		dependenciesByName:   make(map[string]interface{}),
-		dependenciesByType:   make(map[reflect.Type]interface{}),
-		possibleInjectionSet: newInterfaceSet(),
+		dependenciesByName:      make(map[string]interface{}),
+		dependenciesByType:      make(map[reflect.Type]interface{}),
+		dependenciesByInterface: make(map[reflect.Type]interface{}),
+		possibleInjectionSet:    newInterfaceSet(),
+		dependencyIndex:         make(map[interface{}]int),
+		priorities:              make(map[interface{}]int),
+		aliases:                 make(map[string]string),
+		streams:                 make(map[string]*stream),
+		factories:               make(map[string]*factory),
+		factoriesByType:         make(map[reflect.Type]*factory),
+		weightedDependencies:    make(map[reflect.Type][]weightedDependency),
+		strategyGroups:          make(map[string]map[string]interface{}),
+		profiles:                make(map[string]*Container),
+		bindings:                make(map[interface{}][]binding),
+		used:                    newInterfaceSet(),
+		rand:                    rand.New(rand.NewSource(time.Now().UnixNano())),
+		clock:                   realClock{},
 	}
 }
 
@@ -53,198 +300,1844 @@ func NewContainer() *Container {
 // explicit name, as Summer cannot automatically inject by interface (you don't
 // want to do this and cannot do this anyways, since your structs could
 // implement many interfaces you're unaware of)
+//
+// Functions are dependencies like any other, identified by their signature
+// rather than by which function they are. Only the last function added with
+// a given signature is auto injected, so add any others by name.
+//
+// Add panics in the situations where AddE would return an error, which with
+// the container's default settings only happens for a nil target or once the
+// container is frozen.
 func (c *Container) Add(target interface{}, name string) {
+	if err := c.AddE(target, name); err != nil {
+		panic(err)
+	}
+}
+
+// Identical to Add, but returns an error when the dependency can't be added
+// instead of panicking.
+//
+// An error is returned if target is nil, unless the container has AllowNil
+// set and name isn't blank, if the container has StrictNames set and name
+// is already taken, if OnDuplicateType is DuplicateError and a dependency of
+// target's type was already added, or if it has StrictTags set and
+// target's tags are invalid. An error wrapping ErrFrozen is returned if the
+// container is frozen, see Freeze.
+func (c *Container) AddE(target interface{}, name string) error {
+	if err := c.checkFrozen(); err != nil {
+		return err
+	}
+	if err := c.checkName(name); err != nil {
+		return err
+	}
+	if target == nil {
+		return c.addNil(name)
+	}
+	if existing, ok := c.dependenciesByType[reflect.TypeOf(target)]; ok &&
+		c.OnDuplicateType == DuplicateError && !isSameDependency(existing, target) {
+		return errors.New(
+			fmt.Sprintf("Summer: A dependency of type %T has already been added", target))
+	}
+	if c.StrictTags && isPointerToStruct(target) {
+		if err := c.ValidateTags(target); err != nil {
+			return err
+		}
+	}
+
+	c.register(target, name)
+	return nil
+}
+
+// Adds value exactly like AddE, unless err isn't nil, in which case nothing is
+// added and err is returned as is. This suits constructors that return a
+// value and an error:
+//
+//	db, err := sql.Open("postgres", dsn)
+//	if err := container.AddResult(db, err, "DB"); err != nil {
+func (c *Container) AddResult(value interface{}, err error, name string) error {
+	if err != nil {
+		return err
+	}
+	return c.AddE(value, name)
+}
+
+// Adds a nil dependency by name only, as it has no type, if AllowNil is set
+func (c *Container) addNil(name string) error {
+	if !c.AllowNil {
+		return errors.New(
+			fmt.Sprintf("Summer: Cannot add a nil dependency named %q, set AllowNil to allow it", name))
+	}
+	if name == "" {
+		return errors.New("Summer: Cannot add a nil dependency without a name")
+	}
+
+	c.mutex.Lock()
+	c.dependenciesByName[name] = nil
+	c.mutex.Unlock()
+	c.notifyAdd(name, nil)
+	return nil
+}
+
+// Makes the container's registrations read-only, for once wiring is done.
+// Afterwards, every method that adds, replaces or removes dependencies,
+// factories, aliases or bindings fails: those that return an error return
+// one wrapping ErrFrozen, and the rest, including Add, panic with it.
+// Injection, Get and the other read-only methods are unaffected, as are
+// factories constructing their dependencies and settings such as SetLogger.
+//
+// A frozen container can't be thawed, but its clones and scopes aren't
+// frozen, see Clone and EnterScope.
+func (c *Container) Freeze() {
+	c.frozen = true
+}
+
+// Returns an error wrapping ErrFrozen if the container is frozen
+func (c *Container) checkFrozen() error {
+	if c.frozen {
+		return fmt.Errorf("Summer: Cannot change the registrations of a frozen container: %w", ErrFrozen)
+	}
+	return nil
+}
+
+// Returns an error if the container has StrictNames set and name is taken
+func (c *Container) checkName(name string) error {
+	if c.StrictNames && name != "" && c.hasName(name) {
+		return errors.New(
+			fmt.Sprintf("Summer: A dependency named %s has already been added", name))
+	}
+	return nil
+}
+
+// Records the dependency without any of AddE's checks
+func (c *Container) register(target interface{}, name string) {
+	c.mutex.Lock()
+	defer c.notifyAdd(name, reflect.TypeOf(target))
+	defer c.mutex.Unlock()
+
 	if name != "" {
 		c.dependenciesByName[name] = target
 	}
 
-	// Last dependency of a specific type always takes precedence
-	c.dependenciesByType[reflect.TypeOf(target)] = target
+	// Last dependency of a specific type takes precedence, unless told
+	// otherwise
+	if _, ok := c.dependenciesByType[reflect.TypeOf(target)]; !ok || c.OnDuplicateType != DuplicateFirstWins {
+		c.dependenciesByType[reflect.TypeOf(target)] = target
+	}
 
-	// All unique dependencies added once, if they're injectable
-	if isPointerToStruct(target) {
+	// All unique dependencies added once, if they're injectable. Nil pointers
+	// have no struct to inject into.
+	if isPointerToStruct(target) && !reflect.ValueOf(target).IsNil() {
 		c.possibleInjectionSet.Add(target)
 	}
+
+	if c.indexOfDependency(target) < 0 {
+		c.appendDependency(target)
+	}
 }
 
-// Injects dependencies for every struct that has been
-// added to the container. Operates as if InjectInto was called for
-// all objects, with the callbacks ran after all injections take place.
+// Adds a value to be injected, such as a setting or a struct holding
+// configuration, as opposed to a service with dependencies of its own. Values
+// are available by name and type exactly as with Add, but they're never
+// injected into, even when they're pointers to structs with tagged fields.
 //
-// Errors returned are identical to InjectInto's errors.
-func (c *Container) PerformInjections() error {
-	var err error = nil
+// AddValue panics in the same situations as Add.
+func (c *Container) AddValue(value interface{}, name string) {
+	// Structs already added as services stay injectable
+	injectable := isPointerToStruct(value) && c.possibleInjectionSet.Contains(value)
+	c.Add(value, name)
+	if isPointerToStruct(value) && !injectable {
+		c.mutex.Lock()
+		c.possibleInjectionSet.Remove(value)
+		c.mutex.Unlock()
+	}
+}
 
-	c.possibleInjectionSet.EachElement(func(key interface{}) {
-		if err == nil {
-			if err = c.realInjectInto(key, false); err != nil {
-				return
-			}
-		}
-	})
+// Adds the dependency exactly like Add, but only if cond is true.
+func (c *Container) AddIf(cond bool, target interface{}, name string) {
+	if cond {
+		c.Add(target, name)
+	}
+}
 
-	// Run hooks after *all* dependencies are injected successfully
-	if err == nil {
-		c.possibleInjectionSet.EachElement(func(key interface{}) {
-			performPostInjectionHook(key)
-		})
+// Adds each of the targets to the container without a name, exactly as if
+// Add had been called for each in turn.
+func (c *Container) AddAll(targets ...interface{}) {
+	for _, target := range targets {
+		c.Add(target, "")
 	}
+}
 
-	return err
+// Adds each dependency in pairs under its key, exactly as if Add had been
+// called for each. Dependencies are added in order of their names, so when
+// several share a type the last name alphabetically is used for injection
+// by type.
+func (c *Container) AddNamed(pairs map[string]interface{}) {
+	names := make([]string, 0, len(pairs))
+	for name := range pairs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		c.Add(pairs[name], name)
+	}
 }
 
-// Injects the container's stored dependencies into the
-// target implementation by examining the target's struct tags.
-//
-// If the target implements the PostInjector interface, post
-// injection hooks are called after a successful injection.
+// Adds a single dependency under each of the given names. This is the same
+// as calling Add once per name, except that the dependency is only
+// registered by type once. With no names, the dependency is added as if by
+// Add with a blank name.
 //
-// An error is returned if one of the tagged fields requests
-// a named dependency missing in the container.
-// An error is returned if one of the tagged fields requests
-// an automatic injection and no matching type is present in the container.
-// An error is also returned if the target interface{} is not
-// a struct.
-func (c *Container) InjectInto(target interface{}) error {
-	return c.realInjectInto(target, true)
+// AddWithNames panics in the situations where AddE would return an error,
+// before any name is added.
+func (c *Container) AddWithNames(target interface{}, names ...string) {
+	if err := c.addWithNames(target, names); err != nil {
+		panic(err)
+	}
 }
 
-// Actual implementation of InjectInto. Subject to change.
-func (c *Container) realInjectInto(target interface{}, performHook bool) error {
-	if ok := isPointerToStruct(target); !ok {
-		return errors.New("Summer: Attempted to inject into something other than a pointer-to-struct")
+// Like AddWithNames, but returns any error instead of panicking
+func (c *Container) addWithNames(target interface{}, names []string) error {
+	if len(names) == 0 {
+		return c.AddE(target, "")
+	}
+	for _, name := range names[1:] {
+		if err := c.checkName(name); err != nil {
+			return err
+		}
 	}
 
-	err := iterateFields(target, c.performInjection)
-	if err != nil {
+	if err := c.AddE(target, names[0]); err != nil {
 		return err
 	}
-	if performHook {
-		performPostInjectionHook(target)
+	for _, name := range names[1:] {
+		if name != "" {
+			c.mutex.Lock()
+			c.dependenciesByName[name] = target
+			c.mutex.Unlock()
+			c.notifyAdd(name, reflect.TypeOf(target))
+		}
 	}
-
 	return nil
 }
 
-// Returns a named dependency from the container.
-//
-// When the dependency is missing from the container, the second return value
-// is false.
-func (c *Container) Get(name string) (interface{}, bool) {
-	if dependency, ok := c.dependenciesByName[name]; ok {
-		return dependency, true
+// Returns the position of the dependency in c.dependencies, or -1. Values
+// that can't be compared are never the same dependency, see
+// isSameDependency, so they're never found.
+func (c *Container) indexOfDependency(target interface{}) int {
+	if !reflect.ValueOf(target).Comparable() {
+		return -1
 	}
-
-	return nil, false
+	if index, ok := c.dependencyIndex[target]; ok {
+		return index
+	}
+	return -1
 }
 
-func performPostInjectionHook(target interface{}) {
-	if _, ok := target.(PostInjector); ok {
-		target.(PostInjector).PostInjectionCallback()
+// Adds the dependency to the end of c.dependencies
+func (c *Container) appendDependency(target interface{}) {
+	if reflect.ValueOf(target).Comparable() {
+		c.dependencyIndex[target] = len(c.dependencies)
 	}
+	c.dependencies = append(c.dependencies, target)
 }
 
-// Gets the type of target after a dereference (if necessary)
-func getDereferencedType(target interface{}) reflect.Type {
-	targetType := reflect.TypeOf(target)
+// Removes the dependency at index from c.dependencies, moving those after it
+// up by one
+func (c *Container) removeDependency(index int) {
+	if removed := c.dependencies[index]; reflect.ValueOf(removed).Comparable() {
+		delete(c.dependencyIndex, removed)
+	}
+	c.dependencies = append(c.dependencies[:index], c.dependencies[index+1:]...)
+	for position := index; position < len(c.dependencies); position++ {
+		if dependency := c.dependencies[position]; reflect.ValueOf(dependency).Comparable() {
+			c.dependencyIndex[dependency] = position
+		}
+	}
+}
 
-	if targetType.Kind() == reflect.Ptr {
-		targetType = targetType.Elem()
+// Rebuilds c.dependencyIndex after c.dependencies is replaced wholesale
+func (c *Container) reindexDependencies() {
+	c.dependencyIndex = make(map[interface{}]int, len(c.dependencies))
+	for position, dependency := range c.dependencies {
+		if reflect.ValueOf(dependency).Comparable() {
+			c.dependencyIndex[dependency] = position
+		}
 	}
+}
 
-	return targetType
+// Compares dependencies by identity, treating values that can't be compared
+// (such as slices and functions) as distinct
+func isSameDependency(a interface{}, b interface{}) bool {
+	return reflect.ValueOf(a).Comparable() && reflect.ValueOf(b).Comparable() && a == b
 }
 
-func isPointerToStruct(target interface{}) bool {
-	targetType := getDereferencedType(target)
-	return (targetType.Kind() == reflect.Struct)
+// Replaces the dependency registered under name with target. Unlike calling
+// Add again, the previous dependency is also withdrawn from injection by type
+// and, if it was a struct awaiting injection, from PerformInjections. A
+// previous dependency still registered under another name stays in the
+// container, exactly as it was, under that name.
+func (c *Container) Replace(target interface{}, name string) {
+	if err := c.checkFrozen(); err != nil {
+		panic(err)
+	}
+	if previous, ok := c.dependenciesByName[name]; ok && !c.isNamedOtherThan(previous, name) {
+		previousType := reflect.TypeOf(previous)
+		if isSameDependency(c.dependenciesByType[previousType], previous) {
+			delete(c.dependenciesByType, previousType)
+		}
+		for t, dependency := range c.dependenciesByInterface {
+			if isSameDependency(dependency, previous) {
+				delete(c.dependenciesByInterface, t)
+			}
+		}
+		if isPointerToStruct(previous) {
+			c.mutex.Lock()
+			c.possibleInjectionSet.Remove(previous)
+			c.mutex.Unlock()
+		}
+		if index := c.indexOfDependency(previous); index >= 0 {
+			c.removeDependency(index)
+		}
+	}
+
+	c.register(target, name)
 }
 
-// struct to hold the sprawling number of arguments passed around for injection
-type injectionPoint struct {
-	elementType reflect.Type        // The type of the struct we're injecting into
-	field       reflect.Value       // The specific instance of the struct's field we're setting
-	typeField   reflect.StructField // The type's description of the field
+// Whether the dependency is registered under any name besides name
+func (c *Container) isNamedOtherThan(dependency interface{}, name string) bool {
+	for other, named := range c.dependenciesByName {
+		if other != name && isSameDependency(named, dependency) {
+			return true
+		}
+	}
+	return false
 }
 
-// the field tag is parsed into this struct
-type fieldTag struct {
-	dependencyName string
-	autoInject     bool
+// Injects dependencies for every struct that has been
+// added to the container. Operates as if InjectInto was called for
+// all objects, with the callbacks ran after all injections take place.
+// Structs are injected in the order they were added, unless given a
+// priority with AddWithPriority.
+//
+// Any factories and providers that haven't been used yet are constructed
+// first, each provider running after the providers of its arguments.
+//
+// Errors returned are identical to InjectInto's errors. An error is also
+// returned if a factory or provider fails, or if providers depend on each
+// other in a cycle.
+func (c *Container) PerformInjections() error {
+	return c.PerformInjectionsCtx(context.Background())
 }
 
-// Format: `summer:"dependencyName,[autoInject]"`
-func parseFieldTag(rawTag string) *fieldTag {
-	if rawTag == "" {
-		return nil
+// Identical to PerformInjections, but passes ctx to the post injection hooks
+// of targets implementing ContextPostInjector. Cancelling ctx doesn't stop
+// the injections themselves; it's up to the hooks to respect it.
+func (c *Container) PerformInjectionsCtx(ctx context.Context) error {
+	c.used = newInterfaceSet()
+	if err := c.constructAll(); err != nil {
+		return err
 	}
 
-	components := strings.Split(rawTag, ",")
-	shouldAutoInject := false
+	for _, level := range c.injectionLevels() {
+		for _, target := range level {
+			// Nothing would be logged or traced for tagless structs either
+			if c.logger == nil && c.tracer == nil && !c.InjectUntaggedByName && isTagless(target) &&
+				len(c.bindings[target]) == 0 {
+				continue
+			}
+			if err := c.realInjectInto(target, false); err != nil {
+				return err
+			}
+		}
 
-	if len(components) > 1 {
-		shouldAutoInject = (components[1] == tagAutoInject)
+		// Run hooks after *all* dependencies of the level are injected successfully
+		for _, target := range level {
+			if err := c.performPostInjectionHook(ctx, target); err != nil {
+				return err
+			}
+		}
 	}
 
-	return &fieldTag{
-		dependencyName: components[0],
-		autoInject:     shouldAutoInject,
+	for _, fn := range c.readyCallbacks {
+		fn()
 	}
+
+	return nil
 }
 
-func (c *Container) performNamedInjection(p injectionPoint, dependencyName string) error {
-	if dependency, ok := c.dependenciesByName[dependencyName]; ok {
-		p.field.Set(reflect.ValueOf(dependency))
-	} else {
-		return errors.New(
-			fmt.Sprintf("Summer: Missing required dependency %s for %s's field %s",
-				dependencyName, p.elementType, p.typeField.Name))
-	}
+// Injects into every struct in the container again, so that dependencies
+// added since PerformInjections are picked up, such as implementations
+// plugged in at runtime. Structs are visited in the same order as by
+// PerformInjections, but factories aren't constructed up front and post
+// injection hooks and OnReady callbacks aren't called again.
+//
+// Errors are returned as for PerformInjections, stopping at the first.
+func (c *Container) ReinjectAll() error {
+	return c.reinject(c.performInjection)
+}
 
-	return nil
+// Like ReinjectAll, but only fills fields that are currently nil, leaving
+// everything else as it was injected or set since.
+func (c *Container) ReinjectNil() error {
+	return c.reinject(func(p injectionPoint) error {
+		if !isNillable(p.typeField.Type) || !p.field.IsNil() {
+			return nil
+		}
+		return c.performInjection(p)
+	})
 }
 
-func (c *Container) performAutoInjection(p injectionPoint) error {
-	matchingType := p.typeField.Type
-	if dependency, ok := c.dependenciesByType[matchingType]; ok {
-		p.field.Set(reflect.ValueOf(dependency))
-	} else {
-		return errors.New(
-			fmt.Sprintf("Summer: Missing autoinjected dependency %s's field %s"+
-				", searched for type %s "+
-				" (did you attempt to autoinject an interface?)",
-				p.elementType, p.typeField.Name, matchingType))
+func (c *Container) reinject(inject func(p injectionPoint) error) error {
+	for _, level := range c.injectionLevels() {
+		for _, target := range level {
+			if err := c.injectWith(target, false, inject); err != nil {
+				return err
+			}
+		}
 	}
 
 	return nil
 }
 
-func (c *Container) performInjection(p injectionPoint) error {
-	tag := parseFieldTag(p.typeField.Tag.Get(summerTag))
+// Registers a function to be called once PerformInjections has finished
+// wiring the whole container, after every struct's post injection hook.
+// Callbacks run in the order they were registered, and don't run at all if
+// injection fails.
+func (c *Container) OnReady(fn func()) {
+	c.readyCallbacks = append(c.readyCallbacks, fn)
+}
 
-	if tag != nil && p.field.CanSet() {
-		if !tag.autoInject {
-			return c.performNamedInjection(p, tag.dependencyName)
-		} else {
-			return c.performAutoInjection(p)
+// Groups the structs awaiting injection by priority, highest first. Each
+// group keeps the order the structs were added in.
+func (c *Container) injectionLevels() [][]interface{} {
+	var targets []interface{}
+	for _, dependency := range c.dependencies {
+		if reflect.ValueOf(dependency).Comparable() && c.possibleInjectionSet.Contains(dependency) {
+			targets = append(targets, dependency)
 		}
 	}
+	if profile := c.activeRegistrations(); profile != nil {
+		for _, dependency := range profile.dependencies {
+			if reflect.ValueOf(dependency).Comparable() && profile.possibleInjectionSet.Contains(dependency) &&
+				c.indexOfDependency(dependency) < 0 {
+				targets = append(targets, dependency)
+			}
+		}
+	}
+	if len(c.priorities) == 0 {
+		return [][]interface{}{targets}
+	}
+
+	sort.SliceStable(targets, func(i, j int) bool {
+		return c.priorities[targets[i]] > c.priorities[targets[j]]
+	})
+
+	var levels [][]interface{}
+	for index, target := range targets {
+		if index == 0 || c.priorities[target] != c.priorities[targets[index-1]] {
+			levels = append(levels, nil)
+		}
+		levels[len(levels)-1] = append(levels[len(levels)-1], target)
+	}
+
+	return levels
+}
+
+// Adds a dependency exactly like AddE, giving it a priority for
+// PerformInjections. Structs with a higher priority are injected, and have
+// their post injection hooks run, before any struct with a lower priority is
+// injected; a hook can therefore add dependencies needed by lower priority
+// structs. Structs added without a priority have a priority of zero.
+func (c *Container) AddWithPriority(target interface{}, name string, priority int) error {
+	if err := c.AddE(target, name); err != nil {
+		return err
+	}
 
+	if reflect.ValueOf(target).Comparable() {
+		c.priorities[target] = priority
+	}
 	return nil
 }
 
-// Iterate over all of the fields in the given (assumed) struct,
-// calling the callback function for each one
-func iterateFields(target interface{},
+// Injects the container's stored dependencies into the
+// target implementation by examining the target's struct tags.
+//
+// If the target implements the PostInjector interface, post
+// injection hooks are called after a successful injection. Errors from
+// PostInjectorE hooks are returned.
+//
+// An error is returned if one of the tagged fields requests
+// a named dependency missing in the container.
+// An error is returned if one of the tagged fields requests
+// an automatic injection and no matching type is present in the container.
+// An error is also returned if the target interface{} is not
+// a struct.
+//
+// The target must be a pointer to a struct, or a pointer to an interface
+// variable holding one, in which case the struct it holds is injected into.
+// Structs passed by value can't be injected into, as their fields can't be
+// set.
+func (c *Container) InjectInto(target interface{}) error {
+	return c.realInjectInto(target, true)
+}
+
+// Injects into the dependency registered under name, exactly as InjectInto
+// would, for when the caller doesn't hold a reference to it.
+//
+// An error is returned if nothing is registered under name, or if the
+// dependency isn't a pointer to a struct.
+func (c *Container) InjectIntoNamed(name string) error {
+	target, ok := c.Get(name)
+	if !ok {
+		return fmt.Errorf("Summer: Attempted to inject into missing dependency %s: %w",
+			name, ErrMissingDependency)
+	}
+	if !isPointerToStruct(target) {
+		return fmt.Errorf("Summer: Attempted to inject into dependency %s of type %T"+
+			", which isn't a pointer-to-struct: %w",
+			name, target, ErrNotAStruct)
+	}
+
+	return c.InjectInto(target)
+}
+
+// Injects into each element of targets, a slice or array of pointers to
+// structs, exactly as InjectInto would. Every element is injected into even
+// if an earlier one fails, and the errors of any that failed are returned
+// together, each prefixed with the element's index.
+//
+// An error is returned without injecting anything if targets isn't a slice
+// or array whose elements can hold pointers to structs.
+func (c *Container) InjectIntoEach(targets interface{}) error {
+	value := reflect.ValueOf(targets)
+	if value.Kind() != reflect.Slice && value.Kind() != reflect.Array {
+		return errors.New(
+			fmt.Sprintf("Summer: Attempted to inject into each element of %T, which isn't a slice or array",
+				targets))
+	}
+	if elemType := value.Type().Elem(); elemType.Kind() != reflect.Interface &&
+		(elemType.Kind() != reflect.Ptr || elemType.Elem().Kind() != reflect.Struct) {
+		return fmt.Errorf("Summer: Attempted to inject into each element of %T"+
+			", whose elements aren't pointers-to-structs: %w",
+			targets, ErrNotAStruct)
+	}
+
+	var errs []error
+	for index := 0; index < value.Len(); index++ {
+		if err := c.InjectInto(value.Index(index).Interface()); err != nil {
+			errs = append(errs, fmt.Errorf("element %d: %w", index, err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// Like InjectInto, but only injects the fields for which predicate returns
+// true, skipping the rest entirely, so a struct can be injected in phases.
+// Post injection hooks aren't called, as the struct may not be fully
+// injected yet.
+func (c *Container) InjectIntoFiltered(target interface{}, predicate func(field reflect.StructField) bool) error {
+	return c.injectWith(target, false, func(p injectionPoint) error {
+		if !predicate(p.typeField) {
+			return nil
+		}
+		return c.performInjection(p)
+	})
+}
+
+// Identical to InjectInto, but also reports how each field was injected. The
+// report maps the name of each field that was set to the name of the
+// dependency it received, or if it was found by type, to the type of the
+// value injected. For interface fields, this is the implementation's type.
+func (c *Container) InjectIntoReport(target interface{}) (map[string]string, error) {
+	report := make(map[string]string)
+	err := c.injectWith(target, true, func(p injectionPoint) error {
+		injected, err := c.injectPoint(p)
+		if injected != "" {
+			report[p.typeField.Name] = injected
+		}
+		return err
+	})
+
+	return report, err
+}
+
+// Actual implementation of InjectInto. Subject to change.
+func (c *Container) realInjectInto(target interface{}, performHook bool) error {
+	return c.injectWith(target, performHook, c.performInjection)
+}
+
+// Injects into target, calling inject for each field that may need it
+func (c *Container) injectWith(target interface{}, performHook bool,
+	inject func(p injectionPoint) error) error {
+	target = unwrapInterfacePointer(target)
+	if ok := isPointerToStruct(target); !ok {
+		// Structs passed by value are an easy mistake, with an easy fix
+		if targetType := reflect.TypeOf(target); targetType != nil && targetType.Kind() == reflect.Struct {
+			return fmt.Errorf("Summer: Attempted to inject into a %s struct value"+
+				", pass a pointer to it instead: %w",
+				targetType, ErrNotAStruct)
+		}
+		if targetType := reflect.TypeOf(target); targetType != nil && targetType.Kind() == reflect.Ptr &&
+			targetType.Elem().Kind() == reflect.Interface {
+			return fmt.Errorf("Summer: Attempted to inject into a %s holding %T"+
+				", which isn't a pointer-to-struct: %w",
+				targetType, reflect.ValueOf(target).Elem().Interface(), ErrNotAStruct)
+		}
+		return fmt.Errorf("Summer: Attempted to inject into something other than a pointer-to-struct: %w",
+			ErrNotAStruct)
+	}
+	if reflect.ValueOf(target).IsNil() {
+		return fmt.Errorf("Summer: Attempted to inject into a nil %T: %w", target, ErrNotAStruct)
+	}
+
+	var start time.Time
+	if c.CollectStats {
+		start = c.clock.Now()
+	}
+
+	// Untagged fields are only visited when there's a logger to report them,
+	// or when they may be injected by name
+	iterate := iterateTaggedFields
+	if c.logger != nil || c.InjectUntaggedByName {
+		iterate = iterateFields
+	} else if isTagless(target) {
+		iterate = nil
+	}
+
+	var err error
+	span := c.startSpan(spanInject, target)
+	if iterate != nil {
+		err = iterate(target, inject)
+	}
+	if err == nil {
+		err = c.applyBindings(target)
+	}
+	endSpan(span)
+	if c.CollectStats {
+		c.recordInjection(start)
+	}
+	if err != nil {
+		return err
+	}
+	if performHook {
+		return c.performPostInjectionHook(context.Background(), target)
+	}
+
+	return nil
+}
+
+// Returns the pointer-to-struct held by the interface target points to, if
+// it does, and otherwise target itself
+func unwrapInterfacePointer(target interface{}) interface{} {
+	value := reflect.ValueOf(target)
+	if value.Kind() != reflect.Ptr || value.IsNil() || value.Elem().Kind() != reflect.Interface {
+		return target
+	}
+
+	if held := value.Elem().Interface(); isPointerToStruct(held) {
+		return held
+	}
+	return target
+}
+
+// Whether target has no fields to inject into, as is the case for structs
+// that are only ever dependencies. These can skip straight to their hook.
+func isTagless(target interface{}) bool {
+	return len(taggedFieldIndices(getDereferencedType(target))) == 0
+}
+
+// Logs whether each field was skipped, injected or failed to inject to the
+// given logger. A nil logger disables logging.
+func (c *Container) SetLogger(logger Logger) {
+	c.logger = logger
+}
+
+func (c *Container) logf(format string, args ...interface{}) {
+	if c.logger != nil {
+		c.logger.Logf(format, args...)
+	}
+}
+
+// Returns a named dependency from the container.
+//
+// When the dependency is missing from the container, the second return value
+// is false.
+func (c *Container) Get(name string) (interface{}, bool) {
+	return c.resolveName(name, &resolutionTrace{name: name})
+}
+
+// Like Get, but falls back to the dependency of type t when nothing is
+// registered as name, just as a field tagged with both a name and auto is
+// injected.
+func (c *Container) GetOrType(name string, t reflect.Type) (interface{}, bool) {
+	if dependency, ok := c.Get(name); ok {
+		return dependency, true
+	}
+
+	return c.resolveType(t, &resolutionTrace{name: t.String()})
+}
+
+// Returns every dependency assignable to t, such as every implementation of
+// an interface, in the order they were added. This is what an auto injected
+// slice of t receives.
+func (c *Container) GetAllOfType(t reflect.Type) []interface{} {
+	return c.assignableDependencies(t)
+}
+
+// Makes newName refer to the dependency registered as existingName. Aliases
+// are live: if existingName is later replaced, newName resolves to the
+// replacement. A dependency registered directly under newName takes
+// precedence over the alias.
+//
+// An error is returned if nothing is registered as existingName, or if the
+// alias would form a cycle.
+func (c *Container) Alias(newName string, existingName string) error {
+	if err := c.checkFrozen(); err != nil {
+		return err
+	}
+	if !c.hasName(existingName) {
+		return errors.New(
+			fmt.Sprintf("Summer: Cannot alias %s to missing dependency %s", newName, existingName))
+	}
+
+	for name := existingName; name != ""; name = c.aliases[name] {
+		if name == newName {
+			return errors.New(
+				fmt.Sprintf("Summer: Cannot alias %s to %s, which is already an alias of %s",
+					newName, existingName, newName))
+		}
+	}
+
+	c.aliases[newName] = existingName
+	return nil
+}
+
+// Finds a named dependency, preferring streamed registrations
+func (c *Container) lookupName(name string) (interface{}, bool) {
+	if s, ok := c.streams[name]; ok {
+		return s.get()
+	}
+
+	if profile := c.activeRegistrations(); profile != nil {
+		if dependency, ok := profile.dependenciesByName[name]; ok {
+			return dependency, true
+		}
+	}
+
+	if dependency, ok := c.dependenciesByName[name]; ok {
+		return dependency, true
+	}
+
+	return nil, false
+}
+
+// Returns the number of unique dependencies registered with the container.
+// A dependency added under several names, or both by name and by type, is
+// only counted once.
+func (c *Container) Len() int {
+	return len(c.activeDependencies())
+}
+
+// Returns the number of unique dependencies registered with the container
+// for each type.
+func (c *Container) TypeCounts() map[reflect.Type]int {
+	counts := make(map[reflect.Type]int)
+	for _, dependency := range c.activeDependencies() {
+		counts[reflect.TypeOf(dependency)]++
+	}
+	return counts
+}
+
+// Removes every registration from the container, including scoped
+// providers, and forgets which dependencies were used along with any stats
+// and history, leaving it as if it had just been created with NewContainer.
+// Only its settings, such as StrictNames, the logger, interceptors and
+// observers, are kept.
+func (c *Container) Clear() {
+	if err := c.checkFrozen(); err != nil {
+		panic(err)
+	}
+	c.dependenciesByName = make(map[string]interface{})
+	c.dependenciesByType = make(map[reflect.Type]interface{})
+	c.dependenciesByInterface = make(map[reflect.Type]interface{})
+	c.possibleInjectionSet = newInterfaceSet()
+	c.dependencies = nil
+	c.dependencyIndex = make(map[interface{}]int)
+	c.priorities = make(map[interface{}]int)
+	c.aliases = make(map[string]string)
+	c.streams = make(map[string]*stream)
+	c.factories = make(map[string]*factory)
+	c.factoriesByType = make(map[reflect.Type]*factory)
+	c.scoped = nil
+	c.weightedDependencies = make(map[reflect.Type][]weightedDependency)
+	c.strategyGroups = make(map[string]map[string]interface{})
+	c.profiles = make(map[string]*Container)
+	c.bindings = make(map[interface{}][]binding)
+	c.used = newInterfaceSet()
+	c.stats = ContainerStats{}
+	c.history = nil
+}
+
+// A copy of a container's registrations, taken with Snapshot.
+type ContainerSnapshot struct {
+	dependenciesByName      map[string]interface{}
+	dependenciesByType      map[reflect.Type]interface{}
+	dependenciesByInterface map[reflect.Type]interface{}
+	possibleInjectionSet    *interfaceSet
+	dependencies            []interface{}
+	priorities              map[interface{}]int
+	aliases                 map[string]string
+	streams                 map[string]*stream
+	factories               map[string]*factory
+	factoriesByType         map[reflect.Type]*factory
+	scoped                  []*factory
+	weightedDependencies    map[reflect.Type][]weightedDependency
+	strategyGroups          map[string]map[string]interface{}
+	profiles                map[string]*Container
+	bindings                map[interface{}][]binding
+}
+
+// Captures the container's current registrations, including factories,
+// aliases, streams and profiles, so they can later be reinstated with
+// Restore. Only the container's bookkeeping is copied; the dependencies
+// themselves are shared with the container.
+func (c *Container) Snapshot() *ContainerSnapshot {
+	current := &ContainerSnapshot{
+		dependenciesByName:      c.dependenciesByName,
+		dependenciesByType:      c.dependenciesByType,
+		dependenciesByInterface: c.dependenciesByInterface,
+		possibleInjectionSet:    c.possibleInjectionSet,
+		dependencies:            c.dependencies,
+		priorities:              c.priorities,
+		aliases:                 c.aliases,
+		streams:                 c.streams,
+		factories:               c.factories,
+		factoriesByType:         c.factoriesByType,
+		scoped:                  c.scoped,
+		weightedDependencies:    c.weightedDependencies,
+		strategyGroups:          c.strategyGroups,
+		profiles:                c.profiles,
+		bindings:                c.bindings,
+	}
+	return current.copy()
+}
+
+// Replaces the container's registrations with those held by the snapshot.
+// The snapshot is left untouched, so it can be restored more than once.
+func (c *Container) Restore(s *ContainerSnapshot) {
+	if err := c.checkFrozen(); err != nil {
+		panic(err)
+	}
+	restored := s.copy()
+	c.dependenciesByName = restored.dependenciesByName
+	c.dependenciesByType = restored.dependenciesByType
+	c.dependenciesByInterface = restored.dependenciesByInterface
+	c.possibleInjectionSet = restored.possibleInjectionSet
+	c.dependencies = restored.dependencies
+	c.reindexDependencies()
+	c.priorities = restored.priorities
+	c.aliases = restored.aliases
+	c.streams = restored.streams
+	c.factories = restored.factories
+	c.factoriesByType = restored.factoriesByType
+	c.scoped = restored.scoped
+	c.weightedDependencies = restored.weightedDependencies
+	c.strategyGroups = restored.strategyGroups
+	c.profiles = restored.profiles
+	c.bindings = restored.bindings
+}
+
+// Creates an independent copy of the container, with the same settings and
+// registrations, so that either can be changed without affecting the other.
+// As with Snapshot, the dependencies themselves are shared between the two.
+func (c *Container) Clone() *Container {
+	clone := NewContainer()
+	clone.StrictNames = c.StrictNames
+	clone.OnDuplicateType = c.OnDuplicateType
+	clone.ConstructTimeout = c.ConstructTimeout
+	clone.StrictArrays = c.StrictArrays
+	clone.StrictAuto = c.StrictAuto
+	clone.StrictTags = c.StrictTags
+	clone.ConvertPrimitives = c.ConvertPrimitives
+	clone.ExpandEnv = c.ExpandEnv
+	clone.StrictEnv = c.StrictEnv
+	clone.CollectStats = c.CollectStats
+	clone.MatchByUnderlyingType = c.MatchByUnderlyingType
+	clone.InjectUntaggedByName = c.InjectUntaggedByName
+	clone.AllowNil = c.AllowNil
+	clone.AutoMissIsNil = c.AutoMissIsNil
+	clone.TrackHistory = c.TrackHistory
+	clone.HistoryLimit = c.HistoryLimit
+	clone.Restore(c.Snapshot())
+	clone.activeProfile = c.activeProfile
+	clone.parent = c.parent
+	clone.autoReinject.Store(c.autoReinject.Load())
+	clone.rand = c.rand
+	clone.tracer = c.tracer
+	clone.clock = c.clock
+	clone.typeMatcher = c.typeMatcher
+	clone.logger = c.logger
+	clone.readyCallbacks = append(([]func())(nil), c.readyCallbacks...)
+	clone.interceptors = append([]Interceptor(nil), c.interceptors...)
+	clone.observers = append([]*subscription(nil), c.observers...)
+
+	return clone
+}
+
+func (s *ContainerSnapshot) copy() *ContainerSnapshot {
+	copied := &ContainerSnapshot{
+		dependenciesByName:      make(map[string]interface{}, len(s.dependenciesByName)),
+		dependenciesByType:      make(map[reflect.Type]interface{}, len(s.dependenciesByType)),
+		dependenciesByInterface: make(map[reflect.Type]interface{}, len(s.dependenciesByInterface)),
+		possibleInjectionSet:    s.possibleInjectionSet.Copy(),
+		dependencies:            append([]interface{}(nil), s.dependencies...),
+		priorities:              make(map[interface{}]int, len(s.priorities)),
+		aliases:                 make(map[string]string, len(s.aliases)),
+		streams:                 make(map[string]*stream, len(s.streams)),
+		factories:               make(map[string]*factory, len(s.factories)),
+		factoriesByType:         make(map[reflect.Type]*factory, len(s.factoriesByType)),
+		scoped:                  append([]*factory(nil), s.scoped...),
+		weightedDependencies:    make(map[reflect.Type][]weightedDependency, len(s.weightedDependencies)),
+		strategyGroups:          make(map[string]map[string]interface{}, len(s.strategyGroups)),
+		profiles:                make(map[string]*Container, len(s.profiles)),
+		bindings:                make(map[interface{}][]binding, len(s.bindings)),
+	}
+	for name, dependency := range s.dependenciesByName {
+		copied.dependenciesByName[name] = dependency
+	}
+	for t, dependency := range s.dependenciesByType {
+		copied.dependenciesByType[t] = dependency
+	}
+	for t, dependency := range s.dependenciesByInterface {
+		copied.dependenciesByInterface[t] = dependency
+	}
+	for target, priority := range s.priorities {
+		copied.priorities[target] = priority
+	}
+	for newName, existingName := range s.aliases {
+		copied.aliases[newName] = existingName
+	}
+	for name, stream := range s.streams {
+		copied.streams[name] = stream
+	}
+	for name, f := range s.factories {
+		copied.factories[name] = f
+	}
+	for t, f := range s.factoriesByType {
+		copied.factoriesByType[t] = f
+	}
+	for t, candidates := range s.weightedDependencies {
+		copied.weightedDependencies[t] = append([]weightedDependency(nil), candidates...)
+	}
+	for group, strategies := range s.strategyGroups {
+		copied.strategyGroups[group] = make(map[string]interface{}, len(strategies))
+		for key, impl := range strategies {
+			copied.strategyGroups[group][key] = impl
+		}
+	}
+	for profile, registrations := range s.profiles {
+		copied.profiles[profile] = registrations.Clone()
+	}
+	for target, bindings := range s.bindings {
+		copied.bindings[target] = append([]binding(nil), bindings...)
+	}
+
+	return copied
+}
+
+func (c *Container) performPostInjectionHook(ctx context.Context, target interface{}) error {
+	var err error
+	ran := true
+	if hook, ok := target.(ContextPostInjector); ok {
+		span := c.startSpan(spanHook, target)
+		hook.PostInjectionCallbackCtx(ctx)
+		endSpan(span)
+	} else if hook, ok := target.(PostInjectorE); ok {
+		span := c.startSpan(spanHook, target)
+		err = hook.PostInjectionCallback()
+		endSpan(span)
+	} else if hook, ok := target.(PostInjector); ok {
+		span := c.startSpan(spanHook, target)
+		hook.PostInjectionCallback()
+		endSpan(span)
+	} else {
+		ran = false
+	}
+
+	if ran && c.CollectStats {
+		c.stats.HooksRun++
+	}
+	if err != nil {
+		return fmt.Errorf("Summer: Post injection hook for %T failed: %w", target, err)
+	}
+	return nil
+}
+
+// Gets the type of target after a dereference (if necessary)
+func getDereferencedType(target interface{}) reflect.Type {
+	targetType := reflect.TypeOf(target)
+
+	if targetType.Kind() == reflect.Ptr {
+		targetType = targetType.Elem()
+	}
+
+	return targetType
+}
+
+func isPointerToStruct(target interface{}) bool {
+	targetType := reflect.TypeOf(target)
+	return targetType != nil && targetType.Kind() == reflect.Ptr && targetType.Elem().Kind() == reflect.Struct
+}
+
+// struct to hold the sprawling number of arguments passed around for injection
+type injectionPoint struct {
+	elementType reflect.Type        // The type of the struct we're injecting into
+	field       reflect.Value       // The specific instance of the struct's field we're setting
+	typeField   reflect.StructField // The type's description of the field
+	tag         *fieldTag           // The field's parsed summer tag, if any
+	target      interface{}         // The pointer-to-struct we're injecting into
+}
+
+// the field tag is parsed into this struct
+type fieldTag struct {
+	dependencyName string
+	autoInject     bool
+	strategy       string   // How to pick between several candidates, if set
+	orNew          bool     // Allocate a zero value when auto injection misses
+	typeName       string   // Exact type to auto inject, as given by reflect.Type.String
+	inject         bool     // Inject into the field's own fields too
+	self           bool     // Inject the struct into its own field
+	setter         string   // Method to call with the dependency instead of setting the field
+	ifNil          bool     // Leave fields that already hold a value alone
+	order          int      // Fields with lower orders are injected first
+	byFieldName    bool     // Inject the dependency named after the field
+	unknown        []string // Options that weren't recognised, see ValidateTags
+}
+
+// Format: `summer:"dependencyName,[option],..."`, where the options are:
+//
+//	auto           inject by type rather than by name
+//	strategy=name  pick between several candidates, see AddWithWeight and
+//	               AddStrategy
+//	orNew          allocate a struct when auto injection finds nothing
+//	type=name      auto inject the dependency of the named type
+//	inject         inject into the field's struct as well; alone, only that
+//	self           inject the struct being injected into, in place of a name
+//	setter=Method  pass the dependency to the struct's method rather than
+//	               setting the field, which may then be unexported or blank
+//	ifNil          only inject if the field is nil, keeping any value it was
+//	               given beforehand
+//	order=n        inject fields in ascending order of n, which is 0 if not
+//	               given, rather than the order they're declared in; only
+//	               useful when interceptors or setters rely on other fields
+//
+// A name of the form "#n", such as `summer:"#2"`, injects the nth dependency
+// added that suits the field's type, unless a dependency has that exact name.
+//
+// Empty options are ignored, so `summer:"DB,,ifNil"` is the same as
+// `summer:"DB,ifNil"`. A tag of only commas, such as `summer:","`, injects
+// the dependency named after the field, whereas an empty name followed by
+// any options injects by type.
+//
+// Whitespace around each component is ignored. Names containing commas or
+// surrounding whitespace can be wrapped in single quotes, as in
+// `summer:"'My,Weird,Name',auto"`. Everything inside the quotes is kept as
+// is, except that a doubled single quote stands for one.
+//
+// When both a dependency name and autoInject are given, the named dependency
+// is injected if present, falling back to injection by type otherwise.
+func parseFieldTag(rawTag string) *fieldTag {
+	if rawTag == "" {
+		return nil
+	}
+
+	components := splitTag(rawTag)
+	tag := &fieldTag{dependencyName: components[0]}
+
+	for _, option := range components[1:] {
+		switch {
+		case option == tagAutoInject:
+			tag.autoInject = true
+		case strings.HasPrefix(option, tagStrategy):
+			tag.strategy = strings.TrimPrefix(option, tagStrategy)
+		case option == tagOrNew:
+			tag.orNew = true
+		case strings.HasPrefix(option, tagType):
+			tag.typeName = strings.TrimPrefix(option, tagType)
+		case option == tagInject:
+			tag.inject = true
+		case option == tagSelf:
+			tag.self = true
+		case strings.HasPrefix(option, tagSetter):
+			tag.setter = strings.TrimPrefix(option, tagSetter)
+		case option == tagIfNil:
+			tag.ifNil = true
+		case strings.HasPrefix(option, tagOrder):
+			order, err := strconv.Atoi(strings.TrimPrefix(option, tagOrder))
+			if err != nil {
+				tag.unknown = append(tag.unknown, option)
+			}
+			tag.order = order
+		case option == "":
+		default:
+			tag.unknown = append(tag.unknown, option)
+		}
+	}
+	tag.byFieldName = len(components) > 1 && strings.Join(components, "") == ""
+
+	return tag
+}
+
+// Splits a summer tag into its comma separated components, see
+// parseFieldTag for the quoting rules
+func splitTag(rawTag string) []string {
+	var components []string
+	var component []byte
+	quoted := false
+	kept := 0 // Length of the component without trailing unquoted whitespace
+
+	for i := 0; i < len(rawTag); i++ {
+		char := rawTag[i]
+		switch {
+		case char == '\'' && quoted && i+1 < len(rawTag) && rawTag[i+1] == '\'':
+			component = append(component, char)
+			kept = len(component)
+			i++
+		case char == '\'':
+			quoted = !quoted
+			kept = len(component)
+		case char == ',' && !quoted:
+			components = append(components, string(component[:kept]))
+			component, kept = nil, 0
+		case !quoted && (char == ' ' || char == '\t'):
+			if len(component) > 0 {
+				component = append(component, char)
+			}
+		default:
+			component = append(component, char)
+			kept = len(component)
+		}
+	}
+
+	return append(components, string(component[:kept]))
+}
+
+func (c *Container) performNamedInjection(p injectionPoint, dependencyName string) error {
+	if !c.hasName(dependencyName) {
+		if name, index, ok := parseIndexedName(dependencyName); ok {
+			return c.performIndexedInjection(p, name, index)
+		}
+		if ordinal, ok := parseOrdinal(dependencyName); ok {
+			return c.performOrdinalInjection(p, ordinal)
+		}
+	}
+
+	trace := &resolutionTrace{name: dependencyName}
+	dependency, ok := c.resolveName(dependencyName, trace)
+	if !ok {
+		if err := trace.timeoutError(c.ConstructTimeout); err != nil {
+			return err
+		}
+		return fmt.Errorf("Summer: Missing required dependency %s for %s's field %s: %w",
+			trace, p.elementType, p.typeField.Name, ErrMissingDependency)
+	}
+
+	c.markUsed(dependency)
+	return c.injectDependency(p, dependencyName, dependency)
+}
+
+// Sets the field to the dependency found under dependencyName, expanding and
+// converting it first as the container's settings ask
+func (c *Container) injectDependency(p injectionPoint, dependencyName string, dependency interface{}) error {
+	if str, isString := dependency.(string); isString && c.ExpandEnv {
+		expanded, err := c.expandEnv(str)
+		if err != nil {
+			return errors.New(fmt.Sprintf("Summer: Cannot expand dependency %s for %s's field %s: %v",
+				dependencyName, p.elementType, p.typeField.Name, err))
+		}
+		dependency = expanded
+	}
+
+	value, ok := adaptDependency(dependency, p.typeField.Type)
+	if str, isString := dependency.(string); !ok && isString && c.ConvertPrimitives {
+		var err error
+		value, ok, err = convertPrimitive(str, p.typeField.Type)
+		if err != nil {
+			return &ConversionError{
+				Target:    p.elementType,
+				Field:     p.typeField.Name,
+				FieldType: p.typeField.Type,
+				Value:     str,
+				Err:       err,
+			}
+		}
+	}
+	if !ok && c.MatchByUnderlyingType {
+		value, ok = convertUnderlying(dependency, p.typeField.Type)
+	}
+	if !ok {
+		return newTypeMismatchError(p, dependency)
+	}
+
+	return c.setField(p, value)
+}
+
+// Splits names of the form "name[index]", used to refer to a single element
+// of a named slice or array dependency
+func parseIndexedName(dependencyName string) (string, int, bool) {
+	open := strings.LastIndex(dependencyName, "[")
+	if open <= 0 || !strings.HasSuffix(dependencyName, "]") {
+		return "", 0, false
+	}
+
+	index, err := strconv.Atoi(dependencyName[open+1 : len(dependencyName)-1])
+	if err != nil {
+		return "", 0, false
+	}
+
+	return dependencyName[:open], index, true
+}
+
+// Injects a single element of the named slice or array dependency
+func (c *Container) performIndexedInjection(p injectionPoint, dependencyName string, index int) error {
+	trace := &resolutionTrace{name: dependencyName}
+	dependency, ok := c.resolveName(dependencyName, trace)
+	if !ok {
+		if err := trace.timeoutError(c.ConstructTimeout); err != nil {
+			return err
+		}
+		return fmt.Errorf("Summer: Missing required dependency %s for %s's field %s: %w",
+			trace, p.elementType, p.typeField.Name, ErrMissingDependency)
+	}
+
+	c.markUsed(dependency)
+	elements := reflect.ValueOf(dependency)
+	if elements.Kind() != reflect.Slice && elements.Kind() != reflect.Array {
+		return errors.New(
+			fmt.Sprintf("Summer: Cannot index dependency %s of type %T for %s's field %s",
+				dependencyName, dependency, p.elementType, p.typeField.Name))
+	}
+	if index < 0 || index >= elements.Len() {
+		return errors.New(
+			fmt.Sprintf("Summer: Index %d out of range for dependency %s of length %d"+
+				" for %s's field %s",
+				index, dependencyName, elements.Len(), p.elementType, p.typeField.Name))
+	}
+
+	element := elements.Index(index).Interface()
+	value, ok := adaptDependency(element, p.typeField.Type)
+	if !ok {
+		return newTypeMismatchError(p, element)
+	}
+
+	return c.setField(p, value)
+}
+
+// Parses names of the form "#n", used to refer to the nth dependency of the
+// field's type, counting from 1
+func parseOrdinal(dependencyName string) (int, bool) {
+	if !strings.HasPrefix(dependencyName, "#") {
+		return 0, false
+	}
+
+	ordinal, err := strconv.Atoi(dependencyName[1:])
+	return ordinal, err == nil
+}
+
+// Injects the dependency assignable to the field that was added in the
+// given position, for when several can't be told apart by name
+func (c *Container) performOrdinalInjection(p injectionPoint, ordinal int) error {
+	matches := c.assignableDependencies(p.typeField.Type)
+	if ordinal < 1 || ordinal > len(matches) {
+		return fmt.Errorf("Summer: Missing required dependency #%d of type %s for %s's field %s,"+
+			" only %d added: %w",
+			ordinal, p.typeField.Type, p.elementType, p.typeField.Name, len(matches), ErrMissingDependency)
+	}
+
+	dependency := matches[ordinal-1]
+	c.markUsed(dependency)
+	return c.setField(p, reflect.ValueOf(dependency))
+}
+
+func (c *Container) performAutoInjection(p injectionPoint) error {
+	matchingType := p.typeField.Type
+	if p.tag.orNew && (matchingType.Kind() != reflect.Ptr || matchingType.Elem().Kind() != reflect.Struct) {
+		return errors.New(
+			fmt.Sprintf("Summer: Cannot use %s with %s's field %s, type %s"+
+				" isn't a pointer to a struct",
+				tagOrNew, p.elementType, p.typeField.Name, matchingType))
+	}
+
+	if p.tag.typeName != "" {
+		return c.performTypeNameInjection(p)
+	}
+
+	trace := &resolutionTrace{name: matchingType.String()}
+	if dependency, ok := c.resolveType(matchingType, trace); ok {
+		if c.StrictAuto && c.OnDuplicateType == DuplicateLastWins && c.countOfType(matchingType) > 1 {
+			return errors.New(
+				fmt.Sprintf("Summer: Ambiguous autoinjected dependency %s's field %s"+
+					", %d dependencies of type %s were added",
+					p.elementType, p.typeField.Name, c.countOfType(matchingType), matchingType))
+		}
+		c.markUsed(dependency)
+		return c.setField(p, reflect.ValueOf(dependency))
+	} else if dependency, ok := c.resolveUnderlying(matchingType); ok && len(trace.hops) == 0 {
+		c.markUsed(dependency)
+		value, _ := convertUnderlying(dependency, matchingType)
+		return c.setField(p, value)
+	} else if dependency, ok := c.resolveMatched(matchingType); ok && len(trace.hops) == 0 {
+		c.markUsed(dependency)
+		value, ok := adaptDependency(dependency, matchingType)
+		if !ok {
+			return newTypeMismatchError(p, dependency)
+		}
+		return c.setField(p, value)
+	} else if matchingType.Kind() == reflect.Map && len(trace.hops) == 0 {
+		return c.performMapInjection(p)
+	} else if matchingType.Kind() == reflect.Array && len(trace.hops) == 0 {
+		return c.performArrayInjection(p)
+	} else if matchingType.Kind() == reflect.Slice && len(trace.hops) == 0 {
+		return c.performSliceInjection(p)
+	} else if dependency, ok := c.resolveCounterpart(matchingType, trace); ok {
+		c.markUsed(dependency)
+		value, _ := adaptDependency(dependency, matchingType)
+		return c.setField(p, value)
+	} else if dependency, ok := c.resolveBidirectional(matchingType, trace); ok {
+		c.markUsed(dependency)
+		return c.setField(p, reflect.ValueOf(dependency))
+	} else if p.tag.orNew && len(trace.hops) == 0 {
+		return c.setField(p, reflect.New(matchingType.Elem()))
+	} else if c.AutoMissIsNil && len(trace.hops) == 0 {
+		return errLeftUnset
+	}
+	if err := trace.timeoutError(c.ConstructTimeout); err != nil {
+		return err
+	}
+
+	return fmt.Errorf("Summer: Missing autoinjected dependency %s's field %s"+
+		", searched for type %s "+
+		" (did you attempt to autoinject an interface?): %w",
+		p.elementType, p.typeField.Name, trace, ErrMissingDependency)
+}
+
+// Finds the last dependency added that converts to t, if the container has
+// MatchByUnderlyingType set
+func (c *Container) resolveUnderlying(t reflect.Type) (interface{}, bool) {
+	if !c.MatchByUnderlyingType {
+		return nil, false
+	}
+
+	dependencies := c.activeDependencies()
+	for index := len(dependencies) - 1; index >= 0; index-- {
+		if _, ok := convertUnderlying(dependencies[index], t); ok {
+			return dependencies[index], true
+		}
+	}
+	return nil, false
+}
+
+// Replaces how auto injection matches dependencies to a field when none has
+// the field's exact type. The matcher is called with the field's type and
+// the type of each dependency in turn, from the last added, and the first it
+// accepts is injected, so long as it's assignable to the field. For example,
+// to accept anything assignable:
+//
+//	container.SetTypeMatcher(func(field, candidate reflect.Type) bool {
+//		return candidate.AssignableTo(field)
+//	})
+//
+// By default, and with a nil matcher, only exact types match.
+func (c *Container) SetTypeMatcher(fn func(field reflect.Type, candidate reflect.Type) bool) {
+	c.typeMatcher = fn
+}
+
+// Finds the last dependency added that the type matcher accepts for t
+func (c *Container) resolveMatched(t reflect.Type) (interface{}, bool) {
+	if c.typeMatcher == nil {
+		return nil, false
+	}
+
+	dependencies := c.activeDependencies()
+	for index := len(dependencies) - 1; index >= 0; index-- {
+		dependency := dependencies[index]
+		if candidate := reflect.TypeOf(dependency); candidate != nil && c.typeMatcher(t, candidate) {
+			return dependency, true
+		}
+	}
+	return nil, false
+}
+
+// Converts the dependency to t if both are of the same kind and Go allows
+// the conversion, as it does between types with the same underlying type.
+// Interfaces are left to the usual assignability rules.
+func convertUnderlying(dependency interface{}, t reflect.Type) (reflect.Value, bool) {
+	value := reflect.ValueOf(dependency)
+	if !value.IsValid() || t.Kind() == reflect.Interface ||
+		value.Kind() != t.Kind() || !value.Type().ConvertibleTo(t) {
+		return reflect.Value{}, false
+	}
+
+	return value.Convert(t), true
+}
+
+// Finds a dependency for a struct field from its pointer counterpart, or for
+// a pointer-to-struct field from its value counterpart. Either way the field
+// receives a copy: a value field gets a copy of the struct pointed to, while
+// a pointer field gets a pointer to a new copy of the registered struct.
+// Changes made through the field are therefore never seen by the registered
+// dependency, or vice versa.
+//
+// Likewise, a pointer-to-interface field, such as an *io.Writer, is found
+// from the dependency registered under the interface, with AddAs for
+// example, and receives a pointer to a new interface value holding it.
+func (c *Container) resolveCounterpart(t reflect.Type, trace *resolutionTrace) (interface{}, bool) {
+	var counterpart reflect.Type
+	if t.Kind() == reflect.Struct {
+		counterpart = reflect.PtrTo(t)
+	} else if t.Kind() == reflect.Ptr &&
+		(t.Elem().Kind() == reflect.Struct || t.Elem().Kind() == reflect.Interface) {
+		counterpart = t.Elem()
+	} else {
+		return nil, false
+	}
+
+	dependency, ok := c.resolveType(counterpart, trace)
+	if ok && reflect.ValueOf(dependency).Kind() == reflect.Ptr && reflect.ValueOf(dependency).IsNil() {
+		return nil, false
+	}
+
+	return dependency, ok
+}
+
+// Finds a bidirectional channel dependency for a send-only or receive-only
+// channel field of the same element type
+func (c *Container) resolveBidirectional(t reflect.Type, trace *resolutionTrace) (interface{}, bool) {
+	if t.Kind() != reflect.Chan || t.ChanDir() == reflect.BothDir {
+		return nil, false
+	}
+
+	return c.resolveType(reflect.ChanOf(reflect.BothDir, t.Elem()), trace)
+}
+
+// Injects the dependency registered under the type named by the tag's type
+// option, which lets a field typed as an interface pick one implementation
+func (c *Container) performTypeNameInjection(p injectionPoint) error {
+	for dependencyType, dependency := range c.activeTypes() {
+		if dependencyType == nil || dependencyType.String() != p.tag.typeName {
+			continue
+		}
+		if !dependencyType.AssignableTo(p.typeField.Type) {
+			return newTypeMismatchError(p, dependency)
+		}
+		c.markUsed(dependency)
+
+		return c.setField(p, reflect.ValueOf(dependency))
+	}
+
+	return fmt.Errorf("Summer: Missing autoinjected dependency %s's field %s"+
+		", searched for type %s: %w",
+		p.elementType, p.typeField.Name, p.tag.typeName, ErrMissingDependency)
+}
+
+// The number of unique dependencies added with exactly the type t
+func (c *Container) countOfType(t reflect.Type) int {
+	count := 0
+	for _, dependency := range c.activeDependencies() {
+		if reflect.TypeOf(dependency) == t {
+			count++
+		}
+	}
+	return count
+}
+
+// Fills a map field with every named dependency assignable to the map's
+// value type, keyed by the name it was registered under. Maps keyed by
+// reflect.Type are instead filled with the dependency auto injected for
+// each type, keyed by that type, which suits dispatch tables.
+func (c *Container) performMapInjection(p injectionPoint) error {
+	mapType := p.typeField.Type
+	if mapType.Key() == reflectTypeType {
+		return c.performTypeMapInjection(p)
+	}
+	if mapType.Key().Kind() != reflect.String {
+		return errors.New(
+			fmt.Sprintf("Summer: Cannot autoinject %s's field %s, map type %s"+
+				" must be keyed by string or reflect.Type",
+				p.elementType, p.typeField.Name, mapType))
+	}
+
+	m := reflect.MakeMap(mapType)
+	for name, dependency := range c.activeNames() {
+		dependencyType := reflect.TypeOf(dependency)
+		if dependencyType != nil && dependencyType.AssignableTo(mapType.Elem()) {
+			m.SetMapIndex(reflect.ValueOf(name).Convert(mapType.Key()), reflect.ValueOf(dependency))
+			c.markUsed(dependency)
+		}
+	}
+
+	return c.setField(p, m)
+}
+
+var reflectTypeType = reflect.TypeOf((*reflect.Type)(nil)).Elem()
+
+func (c *Container) performTypeMapInjection(p injectionPoint) error {
+	mapType := p.typeField.Type
+	m := reflect.MakeMap(mapType)
+	for dependencyType, dependency := range c.activeTypes() {
+		if dependencyType != nil && dependency != nil && dependencyType.AssignableTo(mapType.Elem()) {
+			m.SetMapIndex(reflect.ValueOf(dependencyType), reflect.ValueOf(dependency))
+			c.markUsed(dependency)
+		}
+	}
+
+	return c.setField(p, m)
+}
+
+// Fills an array field with the dependencies assignable to its element
+// type, in the order they were added. Leftover elements keep their zero
+// value unless the container has StrictArrays set.
+func (c *Container) performArrayInjection(p injectionPoint) error {
+	arrayType := p.typeField.Type
+	matches := c.assignableDependencies(arrayType.Elem())
+
+	if len(matches) > arrayType.Len() || (c.StrictArrays && len(matches) < arrayType.Len()) {
+		return errors.New(
+			fmt.Sprintf("Summer: Found %d dependencies of type %s for %s's field %s"+
+				", which has room for %d",
+				len(matches), arrayType.Elem(), p.elementType, p.typeField.Name, arrayType.Len()))
+	}
+
+	array := reflect.New(arrayType).Elem()
+	for index, dependency := range matches {
+		array.Index(index).Set(reflect.ValueOf(dependency))
+		c.markUsed(dependency)
+	}
+
+	return c.setField(p, array)
+}
+
+// Fills a slice field with every dependency assignable to its element type,
+// as for a registry of plugins implementing an interface. Dependencies that
+// are Prioritized are ordered by ascending priority, otherwise they're in the
+// order they were added.
+func (c *Container) performSliceInjection(p injectionPoint) error {
+	matches := c.assignableDependencies(p.typeField.Type.Elem())
+	sort.SliceStable(matches, func(i, j int) bool {
+		return priorityOf(matches[i]) < priorityOf(matches[j])
+	})
+
+	slice := reflect.MakeSlice(p.typeField.Type, len(matches), len(matches))
+	for index, dependency := range matches {
+		slice.Index(index).Set(reflect.ValueOf(dependency))
+		c.markUsed(dependency)
+	}
+
+	return c.setField(p, slice)
+}
+
+func priorityOf(dependency interface{}) int {
+	if prioritized, ok := dependency.(Prioritized); ok {
+		return prioritized.Priority()
+	}
+	return 0
+}
+
+// The dependencies assignable to t, in the order they were added
+func (c *Container) assignableDependencies(t reflect.Type) []interface{} {
+	var matches []interface{}
+	for _, dependency := range c.activeDependencies() {
+		dependencyType := reflect.TypeOf(dependency)
+		if dependencyType != nil && dependencyType.AssignableTo(t) {
+			matches = append(matches, dependency)
+		}
+	}
+
+	return matches
+}
+
+func (c *Container) performInjection(p injectionPoint) error {
+	_, err := c.injectPoint(p)
+	return err
+}
+
+// Injects a single field, returning what it was injected with: the name of
+// the dependency, or when found another way, the type of the injected value.
+// The result is blank if the field wasn't set.
+func (c *Container) injectPoint(p injectionPoint) (string, error) {
+	p.tag = c.fieldTagFor(p.typeField)
+
+	if p.tag == nil || !p.isSettable() {
+		c.logf("Summer: Skipped %s's field %s", p.elementType, p.typeField.Name)
+		return "", nil
+	}
+
+	if p.tag.ifNil && isNillable(p.typeField.Type) && !p.field.IsNil() {
+		c.logf("Summer: Skipped %s's field %s, which is already set", p.elementType, p.typeField.Name)
+		return "", nil
+	}
+
+	if p.tag.isInjectOnly() {
+		err := c.performNestedInjection(p)
+		c.logInjection(p, "into its own fields", err)
+		return "", err
+	}
+
+	name, err := c.injectField(p)
+	if err == errLeftUnset {
+		c.logf("Summer: Warning: left %s's field %s unset, no dependency of type %s was found",
+			p.elementType, p.typeField.Name, p.typeField.Type)
+		return "", nil
+	}
+	if err == nil && p.tag.inject {
+		err = c.performNestedInjection(p)
+	}
+
+	if name != "" {
+		c.logInjection(p, "by name "+name, err)
+	} else if p.tag.strategy != "" {
+		c.logInjection(p, "using strategy "+p.tag.strategy, err)
+	} else {
+		c.logInjection(p, "by type "+p.typeField.Type.String(), err)
+	}
+	if err != nil {
+		return "", err
+	}
+
+	if name != "" {
+		return name, nil
+	}
+	if p.field.Kind() == reflect.Interface && !p.field.IsNil() {
+		return p.field.Elem().Type().String(), nil
+	}
+	return p.field.Type().String(), nil
+}
+
+func (c *Container) logInjection(p injectionPoint, method string, err error) {
+	if err != nil {
+		c.logf("Summer: Failed to inject %s's field %s: %v", p.elementType, p.typeField.Name, err)
+	} else {
+		c.logf("Summer: Injected %s's field %s %s", p.elementType, p.typeField.Name, method)
+	}
+}
+
+// Sets the field to the struct that contains it
+func (c *Container) performSelfInjection(p injectionPoint) error {
+	if !reflect.TypeOf(p.target).AssignableTo(p.typeField.Type) {
+		return newTypeMismatchError(p, p.target)
+	}
+
+	return c.setField(p, reflect.ValueOf(p.target))
+}
+
+// Whether the field can be injected, either directly or through a setter
+func (p injectionPoint) isSettable() bool {
+	return p.field.CanSet() || p.tag.setter != ""
+}
+
+// Passes value to the method named by the field's setter option, in place of
+// setting the field. The dependency is found using the field's type and tag
+// as usual, so the field's type must be acceptable to the method.
+func callSetter(p injectionPoint, value reflect.Value) error {
+	method := reflect.ValueOf(p.target).MethodByName(p.tag.setter)
+	if !method.IsValid() || method.Type().NumIn() != 1 || !value.Type().AssignableTo(method.Type().In(0)) {
+		return errors.New(
+			fmt.Sprintf("Summer: %s has no method %s accepting %s for field %s",
+				reflect.TypeOf(p.target), p.tag.setter, value.Type(), p.typeField.Name))
+	}
+
+	method.Call([]reflect.Value{value})
+	return nil
+}
+
+// Whether the tag only asks for injection into the field's own fields, as
+// with `summer:",inject"`
+func (t *fieldTag) isInjectOnly() bool {
+	return t.inject && t.dependencyName == "" && !t.autoInject && t.strategy == "" &&
+		t.typeName == "" && !t.self
+}
+
+// Injects into the fields of a struct, or pointer-to-struct, field. A nil
+// pointer is first replaced by a newly allocated struct.
+func (c *Container) performNestedInjection(p injectionPoint) error {
+	fieldType := p.typeField.Type
+	if fieldType.Kind() == reflect.Struct {
+		return c.realInjectInto(p.field.Addr().Interface(), false)
+	}
+
+	if fieldType.Kind() != reflect.Ptr || fieldType.Elem().Kind() != reflect.Struct {
+		return errors.New(
+			fmt.Sprintf("Summer: Cannot use %s with %s's field %s, type %s"+
+				" isn't a struct or a pointer to one",
+				tagInject, p.elementType, p.typeField.Name, fieldType))
+	}
+	if p.field.IsNil() {
+		p.field.Set(reflect.New(fieldType.Elem()))
+	}
+
+	return c.realInjectInto(p.field.Interface(), false)
+}
+
+// Parses the field's summer tag, returning nil for fields that shouldn't be
+// injected.
+//
+// An untagged embedded interface is injected by the name of its type, such
+// as "Logger" for an embedded Logger, but only when a dependency with that
+// name exists. Interfaces embedded for other reasons, like partially
+// implementing them, are left alone.
+func (c *Container) fieldTagFor(field reflect.StructField) *fieldTag {
+	tag := parseFieldTag(field.Tag.Get(summerTag))
+	if tag == nil && isEmbeddedInterface(field) && c.hasName(field.Name) {
+		tag = &fieldTag{dependencyName: field.Name}
+	}
+	if _, tagged := field.Tag.Lookup(summerTag); !tagged && c.InjectUntaggedByName &&
+		field.IsExported() && c.hasName(field.Name) {
+		tag = &fieldTag{dependencyName: field.Name}
+	}
+	if tag != nil && tag.byFieldName {
+		tag.dependencyName = field.Name
+	}
+
+	return tag
+}
+
+// Injects a tagged field, returning the name of the dependency if it was
+// found by name
+func (c *Container) injectField(p injectionPoint) (string, error) {
+	if p.tag.self {
+		return "", c.performSelfInjection(p)
+	} else if p.tag.strategy != "" {
+		return "", c.performStrategyInjection(p, p.tag.strategy)
+	} else if !p.tag.autoInject {
+		return p.tag.dependencyName, c.performNamedInjection(p, p.tag.dependencyName)
+	} else if p.tag.dependencyName != "" {
+		// Named and auto together: the name wins when it's registered
+		if _, ok := c.Get(p.tag.dependencyName); ok {
+			return p.tag.dependencyName, c.performNamedInjection(p, p.tag.dependencyName)
+		}
+	}
+
+	return "", c.performAutoInjection(p)
+}
+
+// Iterate over all of the fields in the given (assumed) struct,
+// calling the callback function for each one, in the order they're injected
+func iterateFields(target interface{},
+	callback func(p injectionPoint) error) error {
+	element := reflect.ValueOf(target).Elem()
+	elementType := element.Type()
+
+	for _, index := range fieldIndices(elementType) {
+		ip := injectionPoint{
+			field:       element.Field(index),
+			typeField:   elementType.Field(index),
+			elementType: elementType,
+			target:      target,
+		}
+
+		err := callback(ip)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Indices of the summer-tagged fields and embedded interfaces of each struct
+// type. Tags can't change at runtime, so they're only looked for once per
+// type.
+var taggedFieldCache = struct {
+	sync.RWMutex
+	indices map[reflect.Type][]int
+}{indices: make(map[reflect.Type][]int)}
+
+func taggedFieldIndices(elementType reflect.Type) []int {
+	taggedFieldCache.RLock()
+	indices, ok := taggedFieldCache.indices[elementType]
+	taggedFieldCache.RUnlock()
+	if ok {
+		return indices
+	}
+
+	indices = []int{}
+	for index := 0; index < elementType.NumField(); index++ {
+		field := elementType.Field(index)
+		if _, tagged := field.Tag.Lookup(summerTag); tagged || isEmbeddedInterface(field) {
+			indices = append(indices, index)
+		}
+	}
+	sortByFieldOrder(elementType, indices)
+
+	taggedFieldCache.Lock()
+	taggedFieldCache.indices[elementType] = indices
+	taggedFieldCache.Unlock()
+
+	return indices
+}
+
+// Indices of every field of each struct type, in the order they're
+// injected, cached for the same reason as taggedFieldCache
+var fieldCache = struct {
+	sync.RWMutex
+	indices map[reflect.Type][]int
+}{indices: make(map[reflect.Type][]int)}
+
+func fieldIndices(elementType reflect.Type) []int {
+	fieldCache.RLock()
+	indices, ok := fieldCache.indices[elementType]
+	fieldCache.RUnlock()
+	if ok {
+		return indices
+	}
+
+	indices = make([]int, elementType.NumField())
+	for index := range indices {
+		indices[index] = index
+	}
+	sortByFieldOrder(elementType, indices)
+
+	fieldCache.Lock()
+	fieldCache.indices[elementType] = indices
+	fieldCache.Unlock()
+
+	return indices
+}
+
+// Sorts field indices by the order given in each field's tag, keeping
+// declaration order otherwise
+func sortByFieldOrder(elementType reflect.Type, indices []int) {
+	orderOf := func(index int) int {
+		if tag := parseFieldTag(elementType.Field(index).Tag.Get(summerTag)); tag != nil {
+			return tag.order
+		}
+		return 0
+	}
+
+	sort.SliceStable(indices, func(i, j int) bool {
+		return orderOf(indices[i]) < orderOf(indices[j])
+	})
+}
+
+func isEmbeddedInterface(field reflect.StructField) bool {
+	return field.Anonymous && field.Type.Kind() == reflect.Interface
+}
+
+// Like iterateFields, but skips any fields that can't be injected: those
+// without a summer tag, other than embedded interfaces
+func iterateTaggedFields(target interface{},
 	callback func(p injectionPoint) error) error {
 	element := reflect.ValueOf(target).Elem()
 	elementType := element.Type()
 
-	for index := 0; index < element.NumField(); index++ {
+	for _, index := range taggedFieldIndices(elementType) {
 		ip := injectionPoint{
 			field:       element.Field(index),
 			typeField:   elementType.Field(index),
 			elementType: elementType,
+			target:      target,
 		}
 
 		err := callback(ip)
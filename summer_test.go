@@ -1,6 +1,14 @@
 package summer
 
-import "testing"
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
 
 func TestSimpleInject(t *testing.T) {
 	type simpleStruct struct {
@@ -59,6 +67,28 @@ func TestCallsPostInjectionHook(t *testing.T) {
 	}
 }
 
+type failingHookStruct struct {
+	err error
+}
+
+func (h *failingHookStruct) PostInjectionCallback() error {
+	return h.err
+}
+
+func TestReportsPostInjectionHookError(t *testing.T) {
+	hookErr := errors.New("not ready")
+	container := NewContainer()
+	container.Add(&failingHookStruct{err: hookErr}, "")
+
+	injectErr := container.InjectInto(&failingHookStruct{err: hookErr})
+	performErr := container.PerformInjections()
+
+	if !errors.Is(injectErr, hookErr) || !errors.Is(performErr, hookErr) {
+		t.Log(injectErr, performErr)
+		t.Fail()
+	}
+}
+
 func TestParsesEmptyFieldTag(t *testing.T) {
 	tag := parseFieldTag("")
 	if tag != nil {
@@ -206,6 +236,1946 @@ func TestSkipsHooksOnFailedInjections(t *testing.T) {
 	}
 }
 
+func TestSnapshotAndRestore(t *testing.T) {
+	type mockStruct struct {
+		Name string `summer:"Name"`
+	}
+	original := new(mockStruct)
+
+	container := NewContainer()
+	container.Add("original", "Name")
+	container.Add(original, "Service")
+	snapshot := container.Snapshot()
+
+	container.Add("mocked", "Name")
+	container.Add(new(mockStruct), "Service")
+	container.Add(42, "Extra")
+	container.Restore(snapshot)
+
+	name, _ := container.Get("Name")
+	service, _ := container.Get("Service")
+	_, hasExtra := container.Get("Extra")
+	if name != "original" || service != original || hasExtra {
+		t.Fail()
+	}
+
+	err := container.PerformInjections()
+	if err != nil || original.Name != "original" {
+		t.Log(err)
+		t.Fail()
+	}
+}
+
+func TestRestoreReinstatesFactoriesAndAliases(t *testing.T) {
+	constructed := 0
+	container := NewContainer()
+	container.AddFactory(func() string {
+		constructed++
+		return "connection"
+	}, "db")
+	snapshot := container.Snapshot()
+
+	container.Get("db")
+	container.Alias("database", "db")
+	container.Restore(snapshot)
+
+	db, hasDB := container.Get("db")
+	_, hasAlias := container.Get("database")
+	if db != "connection" || !hasDB || hasAlias || constructed != 2 {
+		t.Log(db, hasDB, hasAlias, constructed)
+		t.Fail()
+	}
+}
+
+func TestClear(t *testing.T) {
+	type missingStruct struct {
+		Missing string `summer:"Missing"`
+	}
+
+	container := NewContainer()
+	container.Add("value", "Name")
+	container.Add(new(hookTestingStruct), "Hooked")
+	container.Add(new(missingStruct), "")
+	container.Clear()
+
+	_, hasName := container.Get("Name")
+	_, hasHooked := container.Get("Hooked")
+	err := container.PerformInjections()
+	if hasName || hasHooked || err != nil {
+		t.Log(err)
+		t.Fail()
+	}
+}
+
+func TestClearForgetsScopedProvidersAndHistory(t *testing.T) {
+	type simpleStruct struct {
+		Name string `summer:"Name"`
+	}
+
+	container := NewContainer()
+	container.CollectStats = true
+	container.TrackHistory = true
+	container.Add("value", "Name")
+	container.InjectInto(new(simpleStruct))
+	container.AddScoped(func() *scopedTransaction { return new(scopedTransaction) }, "transaction")
+	container.Clear()
+
+	_, hasTransaction := container.EnterScope().Get("transaction")
+	if hasTransaction || len(container.History()) != 0 || container.Stats() != (ContainerStats{}) ||
+		len(container.UnusedDependencies()) != 0 {
+		t.Fail()
+	}
+}
+
+// Builds a pointer to a struct with the given number of string fields, where
+// only the fields at the tagged indices carry a summer tag
+func newWideStruct(numFields int, tagged map[int]string) interface{} {
+	fields := make([]reflect.StructField, numFields)
+	for index := range fields {
+		fields[index] = reflect.StructField{
+			Name: fmt.Sprintf("Field%d", index),
+			Type: reflect.TypeOf(""),
+		}
+		if name, ok := tagged[index]; ok {
+			fields[index].Tag = reflect.StructTag(fmt.Sprintf(`summer:"%s"`, name))
+		}
+	}
+
+	return reflect.New(reflect.StructOf(fields)).Interface()
+}
+
+func TestInjectsWideStruct(t *testing.T) {
+	s := newWideStruct(200, map[int]string{3: "A", 100: "B", 199: "C"})
+
+	container := NewContainer()
+	container.Add("a", "A")
+	container.Add("b", "B")
+	container.Add("c", "C")
+	err := container.InjectInto(s)
+
+	element := reflect.ValueOf(s).Elem()
+	if err != nil || element.Field(3).String() != "a" ||
+		element.Field(100).String() != "b" || element.Field(199).String() != "c" {
+		t.Log(err)
+		t.Fail()
+	}
+	for index := 0; index < element.NumField(); index++ {
+		if index != 3 && index != 100 && index != 199 && element.Field(index).String() != "" {
+			t.Fail()
+		}
+	}
+}
+
+func BenchmarkInjectIntoWideStruct(b *testing.B) {
+	s := newWideStruct(200, map[int]string{3: "A", 100: "B", 199: "C"})
+
+	container := NewContainer()
+	container.Add("a", "A")
+	container.Add("b", "B")
+	container.Add("c", "C")
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		container.InjectInto(s)
+	}
+}
+
+type discardLogger struct{}
+
+func (discardLogger) Logf(format string, args ...interface{}) {}
+
+func BenchmarkInjectIntoWideStructWithLogger(b *testing.B) {
+	s := newWideStruct(200, map[int]string{3: "A", 100: "B", 199: "C"})
+
+	container := NewContainer()
+	container.SetLogger(discardLogger{})
+	container.Add("a", "A")
+	container.Add("b", "B")
+	container.Add("c", "C")
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		container.InjectInto(s)
+	}
+}
+
+type taglessDependency struct {
+	Name  string
+	Count int
+	Next  *taglessDependency
+}
+
+func TestPerformInjectionsWithTaglessStructs(t *testing.T) {
+	type simpleStruct struct {
+		Dependency *taglessDependency `summer:"*summer.taglessDependency,auto"`
+	}
+
+	container := NewContainer()
+	dependency := &taglessDependency{Name: "untouched"}
+	hook := new(hookTestingStruct)
+	s := new(simpleStruct)
+	container.Add(dependency, "")
+	container.Add(hook, "")
+	container.Add(s, "")
+	err := container.PerformInjections()
+
+	if err != nil || s.Dependency != dependency || !hook.called || dependency.Name != "untouched" {
+		t.Log(err)
+		t.Fail()
+	}
+}
+
+func BenchmarkPerformInjectionsTagless(b *testing.B) {
+	container := NewContainer()
+	for i := 0; i < 100; i++ {
+		container.Add(&taglessDependency{Count: i}, "")
+	}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		container.PerformInjections()
+	}
+}
+
+type testHandler interface {
+	Handle() string
+}
+
+type namedHandler string
+
+func (h namedHandler) Handle() string {
+	return string(h)
+}
+
+func TestAutoInjectsNamedMap(t *testing.T) {
+	type simpleStruct struct {
+		Handlers map[string]testHandler `summer:",auto"`
+	}
+
+	container := NewContainer()
+	container.Add(namedHandler("a"), "A")
+	container.Add(namedHandler("b"), "B")
+	container.Add(namedHandler("c"), "C")
+	container.Add("not a handler", "D")
+	s := new(simpleStruct)
+	err := container.InjectInto(s)
+
+	if err != nil || len(s.Handlers) != 3 || s.Handlers["A"].Handle() != "a" ||
+		s.Handlers["B"].Handle() != "b" || s.Handlers["C"].Handle() != "c" {
+		t.Log(err)
+		t.Fail()
+	}
+}
+
+func TestThrowsErrorForNonStringMapKey(t *testing.T) {
+	type simpleStruct struct {
+		Handlers map[int]testHandler `summer:",auto"`
+	}
+
+	container := NewContainer()
+	container.Add(namedHandler("a"), "A")
+	err := container.InjectInto(new(simpleStruct))
+
+	if err == nil {
+		t.Fail()
+	}
+}
+
+func TestReplace(t *testing.T) {
+	type mockStruct struct {
+		Name string `summer:"Name"`
+	}
+	type simpleStruct struct {
+		Named *mockStruct `summer:"Mock"`
+		Auto  *mockStruct `summer:",auto"`
+	}
+	original := new(mockStruct)
+	replacement := new(mockStruct)
+
+	container := NewContainer()
+	container.Add("name", "Name")
+	container.Add(original, "Mock")
+	container.Replace(replacement, "Mock")
+	s := new(simpleStruct)
+	container.Add(s, "")
+	err := container.PerformInjections()
+
+	if err != nil || s.Named != replacement || s.Auto != replacement ||
+		replacement.Name != "name" || original.Name != "" {
+		t.Log(err)
+		t.Fail()
+	}
+}
+
+func TestReplaceKeepsDependencyStillNamedElsewhere(t *testing.T) {
+	type mockStruct struct {
+		Name string `summer:"Name"`
+	}
+	original := new(mockStruct)
+	replacement := new(mockStruct)
+
+	container := NewContainer()
+	container.Add("name", "Name")
+	container.AddWithNames(original, "one", "two")
+	container.Replace(replacement, "one")
+	err := container.PerformInjections()
+
+	two, _ := container.Get("two")
+	if err != nil || two != original || container.Len() != 3 ||
+		original.Name != "name" || replacement.Name != "name" {
+		t.Log(err, container.Len())
+		t.Fail()
+	}
+}
+
+func TestInjectsIndexedElement(t *testing.T) {
+	type worker struct {
+		ID int
+	}
+	type simpleStruct struct {
+		Worker *worker `summer:"workers[2]"`
+	}
+	workers := []*worker{{ID: 0}, {ID: 1}, {ID: 2}}
+
+	container := NewContainer()
+	container.Add(workers, "workers")
+	s := new(simpleStruct)
+	err := container.InjectInto(s)
+
+	if err != nil || s.Worker != workers[2] {
+		t.Log(err)
+		t.Fail()
+	}
+}
+
+func TestThrowsErrorForIndexOutOfRange(t *testing.T) {
+	type simpleStruct struct {
+		Worker string `summer:"workers[3]"`
+	}
+
+	container := NewContainer()
+	container.Add([]string{"a", "b", "c"}, "workers")
+	err := container.InjectInto(new(simpleStruct))
+
+	if err == nil || !strings.Contains(err.Error(), "out of range") {
+		t.Log(err)
+		t.Fail()
+	}
+}
+
+func TestThrowsErrorForMismatchedIndexedElement(t *testing.T) {
+	type simpleStruct struct {
+		N string `summer:"nums[0]"`
+	}
+
+	container := NewContainer()
+	container.Add([]int{1, 2}, "nums")
+	err := container.InjectInto(new(simpleStruct))
+
+	var mismatch *TypeMismatchError
+	if !errors.As(err, &mismatch) {
+		t.Log(err)
+		t.Fail()
+	}
+}
+
+func TestInjectsByOrdinal(t *testing.T) {
+	type worker struct {
+		ID int
+	}
+	type simpleStruct struct {
+		Worker *worker `summer:"#2"`
+	}
+	workers := []*worker{{ID: 1}, {ID: 2}, {ID: 3}}
+
+	container := NewContainer()
+	container.AddAll(workers[0], workers[1], workers[2])
+	s := new(simpleStruct)
+	err := container.InjectInto(s)
+
+	if err != nil || s.Worker != workers[1] {
+		t.Log(err, s.Worker)
+		t.Fail()
+	}
+}
+
+func TestThrowsErrorForOrdinalOutOfRange(t *testing.T) {
+	type simpleStruct struct {
+		Worker string `summer:"#3"`
+	}
+
+	container := NewContainer()
+	container.AddAll("a", "b")
+	err := container.InjectInto(new(simpleStruct))
+
+	if !errors.Is(err, ErrMissingDependency) {
+		t.Log(err)
+		t.Fail()
+	}
+}
+
+type contextHookTestingStruct struct {
+	hookTestingStruct
+	err error
+}
+
+func (h *contextHookTestingStruct) PostInjectionCallbackCtx(ctx context.Context) {
+	h.err = ctx.Err()
+}
+
+func TestCallsContextPostInjectionHook(t *testing.T) {
+	h := new(contextHookTestingStruct)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	container := NewContainer()
+	container.Add(h, "")
+	err := container.PerformInjectionsCtx(ctx)
+
+	if err != nil || h.err != context.Canceled || h.called {
+		t.Log(err)
+		t.Fail()
+	}
+}
+
+func TestNamedInjectionFallsBackToAuto(t *testing.T) {
+	type cache struct {
+		Name string
+	}
+	type simpleStruct struct {
+		Cache *cache `summer:"Cache,auto"`
+	}
+	named := &cache{Name: "named"}
+	typed := &cache{Name: "typed"}
+
+	container := NewContainer()
+	container.Add(typed, "")
+	container.Add(named, "Cache")
+	s := new(simpleStruct)
+	err := container.InjectInto(s)
+	if err != nil || s.Cache != named {
+		t.Log(err)
+		t.Fail()
+	}
+
+	container = NewContainer()
+	container.Add(typed, "")
+	s = new(simpleStruct)
+	err = container.InjectInto(s)
+	if err != nil || s.Cache != typed {
+		t.Log(err)
+		t.Fail()
+	}
+
+	container = NewContainer()
+	err = container.InjectInto(new(simpleStruct))
+	if err == nil {
+		t.Fail()
+	}
+}
+
+func TestLenAndTypeCounts(t *testing.T) {
+	type service struct {
+		Name string
+	}
+	s1, s2 := new(service), new(service)
+
+	container := NewContainer()
+	container.Add(s1, "First")
+	container.Add(s1, "AlsoFirst")
+	container.Add(s2, "")
+	container.Add("a", "A")
+	container.Add("a", "SameA")
+	container.Add("b", "B")
+	container.Add(42, "")
+
+	counts := container.TypeCounts()
+	if container.Len() != 5 || len(counts) != 3 ||
+		counts[reflect.TypeOf(s1)] != 2 || counts[reflect.TypeOf("")] != 2 ||
+		counts[reflect.TypeOf(0)] != 1 {
+		t.Log(container.Len(), counts)
+		t.Fail()
+	}
+}
+
+func TestAllocatesOrNewOnAutoMiss(t *testing.T) {
+	type config struct {
+		Verbose bool
+	}
+	type simpleStruct struct {
+		Config *config `summer:",auto,orNew"`
+	}
+
+	container := NewContainer()
+	s := new(simpleStruct)
+	err := container.InjectInto(s)
+	if err != nil || s.Config == nil || s.Config.Verbose {
+		t.Log(err)
+		t.Fail()
+	}
+
+	registered := &config{Verbose: true}
+	container.Add(registered, "")
+	s = new(simpleStruct)
+	err = container.InjectInto(s)
+	if err != nil || s.Config != registered {
+		t.Log(err)
+		t.Fail()
+	}
+}
+
+func TestThrowsErrorForOrNewOnNonStructPointer(t *testing.T) {
+	type simpleStruct struct {
+		Name string `summer:",auto,orNew"`
+	}
+
+	container := NewContainer()
+	err := container.InjectInto(new(simpleStruct))
+
+	if err == nil {
+		t.Fail()
+	}
+}
+
+type capturingLogger struct {
+	lines []string
+}
+
+func (l *capturingLogger) Logf(format string, args ...interface{}) {
+	l.lines = append(l.lines, fmt.Sprintf(format, args...))
+}
+
+func TestAutoMissIsNil(t *testing.T) {
+	type simpleStruct struct {
+		Cache *memoryCache `summer:",auto"`
+		Name  string       `summer:"name"`
+	}
+	logger := new(capturingLogger)
+
+	container := NewContainer()
+	container.AutoMissIsNil = true
+	container.SetLogger(logger)
+	container.Add("value", "name")
+	s := new(simpleStruct)
+	err := container.InjectInto(s)
+	missingNameErr := container.InjectInto(new(struct {
+		Missing string `summer:"missing"`
+	}))
+
+	if err != nil || s.Cache != nil || s.Name != "value" ||
+		!strings.Contains(strings.Join(logger.lines, "\n"), "Warning: left summer.simpleStruct's field Cache unset") ||
+		!errors.Is(missingNameErr, ErrMissingDependency) {
+		t.Log(err, missingNameErr, logger.lines)
+		t.Fail()
+	}
+}
+
+func TestLogsInjectionSteps(t *testing.T) {
+	type loggedStruct struct {
+		Named   string `summer:"Name"`
+		Auto    int    `summer:",auto"`
+		Ignored bool
+	}
+	logger := new(capturingLogger)
+
+	container := NewContainer()
+	container.SetLogger(logger)
+	container.Add("value", "Name")
+	err := container.InjectInto(new(loggedStruct))
+
+	expected := []string{
+		"Summer: Injected summer.loggedStruct's field Named by name Name",
+		"Summer: Failed to inject summer.loggedStruct's field Auto: ",
+		"Summer: Skipped summer.loggedStruct's field Ignored",
+	}
+	if err == nil || len(logger.lines) != 2 ||
+		logger.lines[0] != expected[0] || !strings.HasPrefix(logger.lines[1], expected[1]) {
+		t.Log(logger.lines)
+		t.Fail()
+	}
+
+	logger.lines = nil
+	container.Add(7, "")
+	err = container.InjectInto(new(loggedStruct))
+	if err != nil || len(logger.lines) != 3 || logger.lines[0] != expected[0] ||
+		logger.lines[1] != "Summer: Injected summer.loggedStruct's field Auto by type int" ||
+		logger.lines[2] != expected[2] {
+		t.Log(logger.lines)
+		t.Fail()
+	}
+}
+
+type arrayWorker struct {
+	ID int
+}
+
+func TestAutoInjectsArrayExactFit(t *testing.T) {
+	type simpleStruct struct {
+		Workers [3]*arrayWorker `summer:",auto"`
+	}
+	w0, w1, w2 := &arrayWorker{0}, &arrayWorker{1}, &arrayWorker{2}
+
+	container := NewContainer()
+	container.StrictArrays = true
+	container.Add(w0, "")
+	container.Add(w1, "")
+	container.Add(w2, "")
+	s := new(simpleStruct)
+	err := container.InjectInto(s)
+
+	if err != nil || s.Workers != [3]*arrayWorker{w0, w1, w2} {
+		t.Log(err)
+		t.Fail()
+	}
+}
+
+func TestAutoInjectsArrayUnderflow(t *testing.T) {
+	type simpleStruct struct {
+		Workers [3]*arrayWorker `summer:",auto"`
+	}
+	w0 := &arrayWorker{0}
+
+	container := NewContainer()
+	container.Add(w0, "")
+	s := new(simpleStruct)
+	err := container.InjectInto(s)
+
+	if err != nil || s.Workers != [3]*arrayWorker{w0, nil, nil} {
+		t.Log(err)
+		t.Fail()
+	}
+
+	container.StrictArrays = true
+	if container.InjectInto(new(simpleStruct)) == nil {
+		t.Fail()
+	}
+}
+
+func TestAutoInjectsArrayOverflow(t *testing.T) {
+	type simpleStruct struct {
+		Workers [1]*arrayWorker `summer:",auto"`
+	}
+
+	container := NewContainer()
+	container.Add(&arrayWorker{0}, "")
+	container.Add(&arrayWorker{1}, "")
+	err := container.InjectInto(new(simpleStruct))
+
+	if err == nil {
+		t.Fail()
+	}
+}
+
+func TestAlias(t *testing.T) {
+	type simpleStruct struct {
+		DB string `summer:"DB"`
+	}
+
+	container := NewContainer()
+	container.Add("primary", "PrimaryDB")
+	err := container.Alias("DB", "PrimaryDB")
+	s := new(simpleStruct)
+	injectErr := container.InjectInto(s)
+	if err != nil || injectErr != nil || s.DB != "primary" {
+		t.Log(err, injectErr)
+		t.Fail()
+	}
+
+	container.Replace("replica", "PrimaryDB")
+	value, ok := container.Get("DB")
+	if !ok || value != "replica" {
+		t.Fail()
+	}
+}
+
+func TestThrowsErrorForAliasCycle(t *testing.T) {
+	container := NewContainer()
+	container.Add("value", "Original")
+	container.Alias("B", "Original")
+	container.Alias("A", "B")
+	err := container.Alias("B", "A")
+
+	value, ok := container.Get("A")
+	if err == nil || !ok || value != "value" {
+		t.Log(err)
+		t.Fail()
+	}
+}
+
+func TestThrowsErrorForAliasOfMissingName(t *testing.T) {
+	container := NewContainer()
+	err := container.Alias("DB", "PrimaryDB")
+
+	if _, ok := container.Get("DB"); err == nil || ok {
+		t.Fail()
+	}
+}
+
+type testCache interface {
+	Kind() string
+}
+
+type redisCache struct{}
+
+func (*redisCache) Kind() string {
+	return "redis"
+}
+
+type memoryCache struct{}
+
+func (*memoryCache) Kind() string {
+	return "memory"
+}
+
+func TestAutoInjectsByTypeName(t *testing.T) {
+	type simpleStruct struct {
+		Redis  testCache `summer:",auto,type=*summer.redisCache"`
+		Memory testCache `summer:",auto,type=*summer.memoryCache"`
+	}
+
+	container := NewContainer()
+	container.Add(new(redisCache), "")
+	container.Add(new(memoryCache), "")
+	s := new(simpleStruct)
+	err := container.InjectInto(s)
+
+	if err != nil || s.Redis.Kind() != "redis" || s.Memory.Kind() != "memory" {
+		t.Log(err)
+		t.Fail()
+	}
+}
+
+func TestThrowsErrorForUnusableTypeName(t *testing.T) {
+	type missingStruct struct {
+		Cache testCache `summer:",auto,type=*summer.diskCache"`
+	}
+	type mismatchedStruct struct {
+		Cache testCache `summer:",auto,type=string"`
+	}
+
+	container := NewContainer()
+	container.Add(new(redisCache), "")
+	container.Add("not a cache", "")
+
+	if container.InjectInto(new(missingStruct)) == nil ||
+		container.InjectInto(new(mismatchedStruct)) == nil {
+		t.Fail()
+	}
+}
+
+func TestAddValueIsNotInjectedInto(t *testing.T) {
+	type config struct {
+		Endpoint string `summer:"endpoint"`
+	}
+	type service struct {
+		Config *config `summer:"config"`
+	}
+
+	container := NewContainer()
+	cfg := &config{Endpoint: "configured"}
+	container.AddValue(cfg, "config")
+	container.Add("overwritten", "endpoint")
+	s := new(service)
+	container.Add(s, "")
+	err := container.PerformInjections()
+
+	if err != nil || s.Config != cfg || cfg.Endpoint != "configured" {
+		t.Log(err, cfg)
+		t.Fail()
+	}
+}
+
+func TestAddNilPanics(t *testing.T) {
+	container := NewContainer()
+	defer func() {
+		if recover() == nil || container.Len() != 0 {
+			t.Fail()
+		}
+	}()
+
+	container.Add(nil, "x")
+}
+
+func TestAllowNil(t *testing.T) {
+	type simpleStruct struct {
+		Cache testCache `summer:"cache"`
+	}
+
+	container := NewContainer()
+	container.AllowNil = true
+	err := container.AddE(nil, "cache")
+	unnamedErr := container.AddE(nil, "")
+	s := &simpleStruct{Cache: new(redisCache)}
+	injectErr := container.InjectInto(s)
+
+	if err != nil || unnamedErr == nil || injectErr != nil || s.Cache != nil {
+		t.Log(err, unnamedErr, injectErr)
+		t.Fail()
+	}
+}
+
+func TestFreezeRejectsChanges(t *testing.T) {
+	type simpleStruct struct {
+		Name string `summer:"name"`
+	}
+
+	container := NewContainer()
+	container.Add("value", "name")
+	container.Freeze()
+	addErr := container.AddE("other", "other")
+	aliasErr := container.Alias("alias", "name")
+	factoryErr := container.AddFactory(func() int { return 1 }, "")
+	s := new(simpleStruct)
+	injectErr := container.InjectInto(s)
+
+	if !errors.Is(addErr, ErrFrozen) || !errors.Is(aliasErr, ErrFrozen) || !errors.Is(factoryErr, ErrFrozen) ||
+		injectErr != nil || s.Name != "value" || container.Len() != 1 {
+		t.Log(addErr, aliasErr, factoryErr, injectErr)
+		t.Fail()
+	}
+
+	defer func() {
+		if err, _ := recover().(error); !errors.Is(err, ErrFrozen) {
+			t.Fail()
+		}
+	}()
+	container.Clear()
+}
+
+func TestFreezeAllowsFactoryConstruction(t *testing.T) {
+	type simpleStruct struct {
+		Value *int `summer:",auto"`
+	}
+
+	container := NewContainer()
+	container.AddFactory(func() *int { return new(int) }, "")
+	container.Freeze()
+	s := new(simpleStruct)
+	err := container.InjectInto(s)
+
+	if err != nil || s.Value == nil {
+		t.Log(err)
+		t.Fail()
+	}
+}
+
+func TestAddResult(t *testing.T) {
+	constructErr := errors.New("connection refused")
+
+	container := NewContainer()
+	failedErr := container.AddResult("failed", constructErr, "failed")
+	addedErr := container.AddResult("added", nil, "added")
+	_, failedAdded := container.Get("failed")
+	added, _ := container.Get("added")
+
+	if failedErr != constructErr || failedAdded || addedErr != nil || added != "added" {
+		t.Log(failedErr, addedErr)
+		t.Fail()
+	}
+}
+
+func TestAutoInjectsTimeValue(t *testing.T) {
+	type simpleStruct struct {
+		Started time.Time  `summer:",auto"`
+		Expires *time.Time `summer:"expires"`
+	}
+	started := time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+	expires := started.Add(time.Hour)
+
+	container := NewContainer()
+	container.Add(started, "")
+	container.AddValue(&expires, "expires")
+	s := new(simpleStruct)
+	container.Add(s, "")
+	err := container.PerformInjections()
+
+	if err != nil || !s.Started.Equal(started) || s.Expires != &expires ||
+		container.possibleInjectionSet.Contains(&expires) || !container.possibleInjectionSet.Contains(s) {
+		t.Log(err, s)
+		t.Fail()
+	}
+}
+
+func TestAddAll(t *testing.T) {
+	type service struct{}
+	s := new(service)
+
+	container := NewContainer()
+	container.AddAll("value", 42, s)
+
+	if container.dependenciesByType[reflect.TypeOf("")] != "value" ||
+		container.dependenciesByType[reflect.TypeOf(0)] != 42 ||
+		container.dependenciesByType[reflect.TypeOf(s)] != s || container.Len() != 3 {
+		t.Fail()
+	}
+}
+
+func TestAddNamed(t *testing.T) {
+	container := NewContainer()
+	container.AddNamed(map[string]interface{}{
+		"A": "a",
+		"B": 2,
+		"C": "c",
+	})
+
+	a, okA := container.Get("A")
+	b, okB := container.Get("B")
+	c, okC := container.Get("C")
+	if !okA || !okB || !okC || a != "a" || b != 2 || c != "c" ||
+		container.dependenciesByType[reflect.TypeOf("")] != "c" {
+		t.Fail()
+	}
+}
+
+func TestStrictNamesRejectsDuplicates(t *testing.T) {
+	container := NewContainer()
+	container.StrictNames = true
+	first := container.AddE("first", "Name")
+	second := container.AddE("second", "Name")
+
+	value, _ := container.Get("Name")
+	if first != nil || second == nil || value != "first" {
+		t.Fail()
+	}
+}
+
+func TestDuplicateNamesOverwriteByDefault(t *testing.T) {
+	container := NewContainer()
+	first := container.AddE("first", "Name")
+	second := container.AddE("second", "Name")
+
+	value, _ := container.Get("Name")
+	if first != nil || second != nil || value != "second" {
+		t.Fail()
+	}
+}
+
+type embeddedLogger interface {
+	Log(message string) string
+}
+
+type prefixLogger struct {
+	prefix string
+}
+
+func (l *prefixLogger) Log(message string) string {
+	return l.prefix + message
+}
+
+func TestInjectsEmbeddedInterfaceByTypeName(t *testing.T) {
+	type Logger = embeddedLogger
+	type simpleStruct struct {
+		Logger
+	}
+
+	container := NewContainer()
+	container.Add(&prefixLogger{prefix: "> "}, "Logger")
+	s := new(simpleStruct)
+	err := container.InjectInto(s)
+
+	if err != nil || s.Logger == nil || s.Log("hi") != "> hi" {
+		t.Log(err)
+		t.Fail()
+	}
+}
+
+func TestInjectsEmbeddedInterfaceByTag(t *testing.T) {
+	type Logger = embeddedLogger
+	type simpleStruct struct {
+		Logger `summer:"AuditLogger"`
+	}
+
+	container := NewContainer()
+	container.Add(&prefixLogger{prefix: "audit: "}, "AuditLogger")
+	s := new(simpleStruct)
+	err := container.InjectInto(s)
+
+	if err != nil || s.Logger == nil || s.Log("hi") != "audit: hi" {
+		t.Log(err)
+		t.Fail()
+	}
+}
+
+func TestSkipsUnregisteredEmbeddedInterface(t *testing.T) {
+	type Logger = embeddedLogger
+	type simpleStruct struct {
+		Logger
+	}
+
+	container := NewContainer()
+	s := new(simpleStruct)
+	err := container.InjectInto(s)
+
+	if err != nil || s.Logger != nil {
+		t.Log(err)
+		t.Fail()
+	}
+}
+
+func TestInjectUntaggedByName(t *testing.T) {
+	type simpleStruct struct {
+		Host    string `json:"host"`
+		Port    int
+		Timeout int
+		name    string
+	}
+
+	container := NewContainer()
+	container.InjectUntaggedByName = true
+	container.AddNamed(map[string]interface{}{"Host": "localhost", "Port": 8080, "name": "unexported"})
+	s := new(simpleStruct)
+	container.Add(s, "")
+	err := container.PerformInjections()
+
+	if err != nil || s.Host != "localhost" || s.Port != 8080 || s.Timeout != 0 || s.name != "" {
+		t.Log(err, s)
+		t.Fail()
+	}
+}
+
+func TestUntaggedFieldsNotInjectedByDefault(t *testing.T) {
+	type simpleStruct struct {
+		Host string
+	}
+
+	container := NewContainer()
+	container.Add("localhost", "Host")
+	s := new(simpleStruct)
+	err := container.InjectInto(s)
+
+	if err != nil || s.Host != "" {
+		t.Log(err)
+		t.Fail()
+	}
+}
+
+type registeringHookStruct struct {
+	container *Container
+}
+
+func (r *registeringHookStruct) PostInjectionCallback() {
+	r.container.Add("registered by hook", "FromHook")
+}
+
+func TestReinjectAllPicksUpLaterDependencies(t *testing.T) {
+	type simpleStruct struct {
+		Cache   testCache `summer:"cache"`
+		Version string    `summer:"version"`
+	}
+	s := new(simpleStruct)
+
+	container := NewContainer()
+	container.AddTyped(nil, reflect.TypeOf((*testCache)(nil)).Elem(), "cache")
+	container.Add("1", "version")
+	container.Add(s, "")
+	err := container.PerformInjections()
+	cache := new(redisCache)
+	container.Add(cache, "cache")
+	container.Add("2", "version")
+	reinjectErr := container.ReinjectAll()
+
+	if err != nil || reinjectErr != nil || s.Cache != cache || s.Version != "2" {
+		t.Log(err, reinjectErr, s)
+		t.Fail()
+	}
+}
+
+func TestReinjectNilOnlyFillsNilFields(t *testing.T) {
+	type simpleStruct struct {
+		Cache   testCache `summer:"cache"`
+		Version string    `summer:"version"`
+	}
+	s := new(simpleStruct)
+
+	container := NewContainer()
+	container.AddTyped(nil, reflect.TypeOf((*testCache)(nil)).Elem(), "cache")
+	container.Add("1", "version")
+	container.Add(s, "")
+	err := container.PerformInjections()
+	cache := new(redisCache)
+	container.Add(cache, "cache")
+	container.Add("2", "version")
+	reinjectErr := container.ReinjectNil()
+
+	if err != nil || reinjectErr != nil || s.Cache != cache || s.Version != "1" {
+		t.Log(err, reinjectErr, s)
+		t.Fail()
+	}
+}
+
+func TestPriorityRunsHooksBeforeLowerPriorityInjection(t *testing.T) {
+	type dependentStruct struct {
+		Value string `summer:"FromHook"`
+	}
+	dependent := new(dependentStruct)
+
+	container := NewContainer()
+	container.AddWithPriority(dependent, "", -1)
+	container.AddWithPriority(&registeringHookStruct{container: container}, "", 10)
+	err := container.PerformInjections()
+
+	if err != nil || dependent.Value != "registered by hook" {
+		t.Log(err)
+		t.Fail()
+	}
+}
+
+func TestPerformInjectionsWithUncomparableDependencies(t *testing.T) {
+	container := NewContainer()
+	container.Add([]string{"a"}, "Slice")
+	container.Add(map[string]int{}, "")
+	container.AddWithPriority(func() {}, "", 1)
+	err := container.PerformInjections()
+
+	if err != nil {
+		t.Fail()
+	}
+}
+
+func TestInjectsIntoNestedStructFields(t *testing.T) {
+	type subService struct {
+		Name string `summer:"Name"`
+	}
+	type simpleStruct struct {
+		Sub        subService  `summer:",inject"`
+		Allocated  *subService `summer:",inject"`
+		Registered *subService `summer:",auto,inject"`
+		Ignored    subService
+	}
+	registered := new(subService)
+
+	container := NewContainer()
+	container.Add("nested", "Name")
+	container.Add(registered, "")
+	s := new(simpleStruct)
+	err := container.InjectInto(s)
+
+	if err != nil || s.Sub.Name != "nested" || s.Allocated == nil || s.Allocated.Name != "nested" ||
+		s.Registered != registered || registered.Name != "nested" || s.Ignored.Name != "" {
+		t.Log(err)
+		t.Fail()
+	}
+}
+
+func TestInjectIntoReport(t *testing.T) {
+	type simpleStruct struct {
+		Named     string    `summer:"Name"`
+		Auto      int       `summer:",auto"`
+		Interface testCache `summer:"Cache,auto"`
+		ByType    testCache `summer:",auto,type=*summer.memoryCache"`
+		Untagged  string
+	}
+
+	container := NewContainer()
+	container.Add("value", "Name")
+	container.Add(7, "")
+	container.Add(new(redisCache), "Cache")
+	container.Add(new(memoryCache), "")
+	report, err := container.InjectIntoReport(new(simpleStruct))
+
+	expected := map[string]string{
+		"Named":     "Name",
+		"Auto":      "int",
+		"Interface": "Cache",
+		"ByType":    "*summer.memoryCache",
+	}
+	if err != nil || !reflect.DeepEqual(report, expected) {
+		t.Log(err, report)
+		t.Fail()
+	}
+}
+
+type selfReferencingNode struct {
+	Self *selfReferencingNode `summer:",self"`
+}
+
+func TestInjectsSelf(t *testing.T) {
+	node := new(selfReferencingNode)
+
+	container := NewContainer()
+	err := container.InjectInto(node)
+
+	if err != nil || node.Self != node {
+		t.Log(err)
+		t.Fail()
+	}
+}
+
+func TestThrowsErrorForMismatchedSelf(t *testing.T) {
+	type simpleStruct struct {
+		Self string `summer:",self"`
+	}
+
+	container := NewContainer()
+	err := container.InjectInto(new(simpleStruct))
+
+	if err == nil {
+		t.Fail()
+	}
+}
+
+func TestOnReadyRunsAfterSuccessfulInjection(t *testing.T) {
+	container := NewContainer()
+	hook := new(hookTestingStruct)
+	container.Add(hook, "hook")
+	var calls []string
+	container.OnReady(func() {
+		if hook.called {
+			calls = append(calls, "first")
+		}
+	})
+	container.OnReady(func() { calls = append(calls, "second") })
+	err := container.PerformInjections()
+
+	if err != nil || len(calls) != 2 || calls[0] != "first" || calls[1] != "second" {
+		t.Log(err, calls)
+		t.Fail()
+	}
+}
+
+func TestOnReadyDoesNotRunAfterFailedInjection(t *testing.T) {
+	type simpleStruct struct {
+		Missing string `summer:"missing"`
+	}
+
+	container := NewContainer()
+	container.Add(new(simpleStruct), "simple")
+	called := false
+	container.OnReady(func() { called = true })
+	err := container.PerformInjections()
+
+	if err == nil || called {
+		t.Fail()
+	}
+}
+
+func TestGetOrTypeFallsBackToType(t *testing.T) {
+	container := NewContainer()
+	handler := namedHandler("a")
+	container.Add(handler, "")
+	dependency, ok := container.GetOrType("handler", reflect.TypeOf(handler))
+
+	if !ok || dependency != handler {
+		t.Fail()
+	}
+}
+
+func TestGetOrTypePrefersName(t *testing.T) {
+	container := NewContainer()
+	container.Add(namedHandler("b"), "handler")
+	container.Add(namedHandler("a"), "")
+	dependency, ok := container.GetOrType("handler", reflect.TypeOf(namedHandler("")))
+	_, missing := container.GetOrType("other", reflect.TypeOf(""))
+
+	if !ok || dependency != namedHandler("b") || missing {
+		t.Fail()
+	}
+}
+
+func TestCloneIsIndependentOfOriginal(t *testing.T) {
+	type simpleStruct struct {
+		Name string `summer:"name"`
+	}
+
+	container := NewContainer()
+	container.StrictNames = true
+	container.Add("original", "name")
+	clone := container.Clone()
+	clone.Replace("cloned", "name")
+	clone.Add("extra", "extra")
+
+	original := new(simpleStruct)
+	cloned := new(simpleStruct)
+	err := container.InjectInto(original)
+	cloneErr := clone.InjectInto(cloned)
+
+	if err != nil || cloneErr != nil || original.Name != "original" || cloned.Name != "cloned" ||
+		container.Len() != 1 || clone.Len() != 2 || !clone.StrictNames {
+		t.Log(err, cloneErr)
+		t.Fail()
+	}
+	if _, ok := container.Get("extra"); ok {
+		t.Fail()
+	}
+}
+
+func TestAutoInjectsChannels(t *testing.T) {
+	type event struct{}
+	type simpleStruct struct {
+		Events   chan event   `summer:",auto"`
+		Receiver <-chan event `summer:",auto"`
+		Sender   chan<- event `summer:",auto"`
+	}
+
+	container := NewContainer()
+	events := make(chan event, 1)
+	container.Add(events, "")
+	s := new(simpleStruct)
+	err := container.InjectInto(s)
+
+	if err != nil || s.Events != events || s.Receiver == nil || s.Sender == nil {
+		t.Log(err)
+		t.FailNow()
+	}
+	s.Sender <- event{}
+	<-s.Receiver
+}
+
+func TestInjectsNamedChannelIntoReceiveOnlyField(t *testing.T) {
+	type simpleStruct struct {
+		Receiver <-chan int `summer:"numbers"`
+	}
+
+	container := NewContainer()
+	numbers := make(chan int, 1)
+	container.Add(numbers, "numbers")
+	s := new(simpleStruct)
+	err := container.InjectInto(s)
+
+	if err != nil || s.Receiver == nil {
+		t.Log(err)
+		t.FailNow()
+	}
+	numbers <- 1
+	if <-s.Receiver != 1 {
+		t.Fail()
+	}
+}
+
+type setterTestingStruct struct {
+	logger   Logger `summer:"logger,setter=SetLogger"`
+	_        string `summer:"prefix,setter=SetPrefix"`
+	_        int    `summer:",auto,setter=SetRetries"`
+	received Logger
+	prefix   string
+	retries  int
+}
+
+func (s *setterTestingStruct) SetLogger(logger Logger) {
+	s.received = logger
+}
+
+func (s *setterTestingStruct) SetPrefix(prefix string) {
+	s.prefix = prefix
+}
+
+func (s *setterTestingStruct) SetRetries(retries int) {
+	s.retries = retries
+}
+
+func TestInjectsThroughSetters(t *testing.T) {
+	container := NewContainer()
+	logger := new(capturingLogger)
+	container.Add(logger, "logger")
+	container.Add("app", "prefix")
+	container.Add(3, "")
+	s := new(setterTestingStruct)
+	err := container.InjectInto(s)
+
+	if err != nil || s.received != logger || s.prefix != "app" || s.retries != 3 || s.logger != nil {
+		t.Log(err)
+		t.Fail()
+	}
+}
+
+func TestThrowsErrorForMissingSetter(t *testing.T) {
+	type simpleStruct struct {
+		_ string `summer:"prefix,setter=SetPrefix"`
+	}
+
+	container := NewContainer()
+	container.Add("app", "prefix")
+	err := container.InjectInto(new(simpleStruct))
+
+	if err == nil || !strings.Contains(err.Error(), "SetPrefix") {
+		t.Log(err)
+		t.Fail()
+	}
+}
+
+func TestMissingDependencyErrorsWrapSentinel(t *testing.T) {
+	type namedStruct struct {
+		Name string `summer:"name"`
+	}
+	type autoStruct struct {
+		Cache *memoryCache `summer:",auto"`
+	}
+
+	container := NewContainer()
+	namedErr := container.InjectInto(new(namedStruct))
+	autoErr := container.InjectInto(new(autoStruct))
+
+	if !errors.Is(namedErr, ErrMissingDependency) || !errors.Is(autoErr, ErrMissingDependency) {
+		t.Log(namedErr, autoErr)
+		t.Fail()
+	}
+	if !strings.HasPrefix(namedErr.Error(), "Summer: Missing required dependency name") {
+		t.Log(namedErr)
+		t.Fail()
+	}
+}
+
+func TestNonStructErrorWrapsSentinel(t *testing.T) {
+	container := NewContainer()
+	value := 3
+	err := container.InjectInto(&value)
+
+	if !errors.Is(err, ErrNotAStruct) {
+		t.Log(err)
+		t.Fail()
+	}
+}
+
+func TestInjectsFunctions(t *testing.T) {
+	type simpleStruct struct {
+		Auto  func(int) string `summer:",auto"`
+		Named func(int) string `summer:"format"`
+	}
+
+	container := NewContainer()
+	container.Add(func(i int) string { return fmt.Sprint(i) }, "format")
+	s := new(simpleStruct)
+	err := container.InjectInto(s)
+
+	if err != nil || s.Auto == nil || s.Auto(1) != "1" || s.Named == nil || s.Named(2) != "2" {
+		t.Log(err)
+		t.Fail()
+	}
+}
+
+func TestThrowsErrorForMismatchedFunction(t *testing.T) {
+	type simpleStruct struct {
+		Named func(string) string `summer:"format"`
+	}
+
+	container := NewContainer()
+	container.Add(func(i int) string { return fmt.Sprint(i) }, "format")
+	err := container.InjectInto(new(simpleStruct))
+
+	var mismatch *TypeMismatchError
+	if !errors.As(err, &mismatch) {
+		t.Log(err)
+		t.Fail()
+	}
+}
+
+func TestThrowsErrorForStructValue(t *testing.T) {
+	type simpleStruct struct {
+		Name string `summer:"name"`
+	}
+
+	container := NewContainer()
+	container.Add("value", "name")
+	err := container.InjectInto(simpleStruct{})
+
+	if !errors.Is(err, ErrNotAStruct) || !strings.Contains(err.Error(), "pass a pointer") {
+		t.Log(err)
+		t.Fail()
+	}
+}
+
+func TestThrowsErrorForNonStruct(t *testing.T) {
+	container := NewContainer()
+	err := container.InjectInto(3)
+
+	if !errors.Is(err, ErrNotAStruct) || strings.Contains(err.Error(), "pass a pointer") {
+		t.Log(err)
+		t.Fail()
+	}
+}
+
+func TestInjectsIntoStructHeldByInterfacePointer(t *testing.T) {
+	type simpleStruct struct {
+		hookTestingStruct
+		Name string `summer:"name"`
+	}
+	s := new(simpleStruct)
+	var held interface{} = s
+
+	container := NewContainer()
+	container.Add("value", "name")
+	err := container.InjectInto(&held)
+
+	if err != nil || s.Name != "value" || !s.called {
+		t.Log(err)
+		t.Fail()
+	}
+}
+
+func TestThrowsErrorForInterfacePointerHoldingNonPointer(t *testing.T) {
+	type simpleStruct struct {
+		Name string `summer:"name"`
+	}
+	var held interface{} = simpleStruct{}
+	var empty interface{}
+
+	container := NewContainer()
+	container.Add("value", "name")
+	heldErr := container.InjectInto(&held)
+	emptyErr := container.InjectInto(&empty)
+
+	if !errors.Is(heldErr, ErrNotAStruct) || !strings.Contains(heldErr.Error(), "holding summer.simpleStruct") ||
+		!errors.Is(emptyErr, ErrNotAStruct) {
+		t.Log(heldErr, emptyErr)
+		t.Fail()
+	}
+}
+
+func TestInjectIntoNamed(t *testing.T) {
+	type simpleStruct struct {
+		Name string `summer:"name"`
+	}
+	s := new(simpleStruct)
+
+	container := NewContainer()
+	container.Add("value", "name")
+	container.Add(s, "service")
+	err := container.InjectIntoNamed("service")
+	missingErr := container.InjectIntoNamed("missing")
+	valueErr := container.InjectIntoNamed("name")
+
+	if err != nil || s.Name != "value" ||
+		!errors.Is(missingErr, ErrMissingDependency) || !errors.Is(valueErr, ErrNotAStruct) {
+		t.Log(err, missingErr, valueErr)
+		t.Fail()
+	}
+}
+
+func TestInjectIntoEach(t *testing.T) {
+	type service struct {
+		Name string `summer:"name"`
+	}
+	services := []*service{new(service), new(service)}
+
+	container := NewContainer()
+	container.Add("value", "name")
+	err := container.InjectIntoEach(services)
+
+	if err != nil || services[0].Name != "value" || services[1].Name != "value" {
+		t.Log(err)
+		t.Fail()
+	}
+}
+
+func TestInjectIntoEachAggregatesErrors(t *testing.T) {
+	type service struct {
+		Name string `summer:"name"`
+	}
+	type other struct {
+		Port int `summer:"port"`
+	}
+	var targets [3]interface{}
+	targets[0], targets[1], targets[2] = new(other), new(service), new(other)
+
+	container := NewContainer()
+	container.Add("value", "name")
+	err := container.InjectIntoEach(targets)
+	valuesErr := container.InjectIntoEach([]service{{}})
+	notSliceErr := container.InjectIntoEach(new(service))
+
+	if !errors.Is(err, ErrMissingDependency) || !strings.Contains(err.Error(), "element 2") ||
+		targets[1].(*service).Name != "value" ||
+		!errors.Is(valuesErr, ErrNotAStruct) || notSliceErr == nil {
+		t.Log(err, valuesErr, notSliceErr)
+		t.Fail()
+	}
+}
+
+func TestInjectIntoEachRejectsNilElements(t *testing.T) {
+	type service struct {
+		Name string `summer:"name"`
+	}
+	services := []*service{new(service), nil}
+
+	container := NewContainer()
+	container.Add("value", "name")
+	err := container.InjectIntoEach(services)
+
+	if !errors.Is(err, ErrNotAStruct) || !strings.Contains(err.Error(), "element 1") ||
+		services[0].Name != "value" {
+		t.Log(err)
+		t.Fail()
+	}
+}
+
+func TestInjectIntoFiltered(t *testing.T) {
+	type simpleStruct struct {
+		hookTestingStruct
+		DBHost  string `summer:"host"`
+		DBPort  int    `summer:"port"`
+		Missing string `summer:"missing"`
+	}
+
+	container := NewContainer()
+	container.Add("localhost", "host")
+	container.Add(5432, "port")
+	s := new(simpleStruct)
+	err := container.InjectIntoFiltered(s, func(field reflect.StructField) bool {
+		return strings.HasPrefix(field.Name, "DB")
+	})
+
+	if err != nil || s.DBHost != "localhost" || s.DBPort != 5432 || s.called {
+		t.Log(err, s)
+		t.Fail()
+	}
+}
+
+func TestPerformInjectionsSkipsStructValues(t *testing.T) {
+	type simpleStruct struct {
+		Name string `summer:"name"`
+	}
+
+	container := NewContainer()
+	container.Add("value", "name")
+	container.Add(simpleStruct{}, "value")
+	err := container.PerformInjections()
+
+	if err != nil {
+		t.Log(err)
+		t.Fail()
+	}
+}
+
+type upperHandler string
+
+func (h upperHandler) Handle() string {
+	return strings.ToUpper(string(h))
+}
+
+func TestAutoInjectsSliceOfInterfaces(t *testing.T) {
+	type simpleStruct struct {
+		Handlers []testHandler `summer:",auto"`
+	}
+
+	container := NewContainer()
+	container.Add(upperHandler("a"), "")
+	container.Add("not a handler", "")
+	container.Add(namedHandler("b"), "B")
+	container.Add(&wrappedHandler{inner: namedHandler("c")}, "")
+	s := new(simpleStruct)
+	err := container.InjectInto(s)
+
+	if err != nil || len(s.Handlers) != 3 || s.Handlers[0].Handle() != "A" ||
+		s.Handlers[1].Handle() != "b" || s.Handlers[2].Handle() != "wrapped c" {
+		t.Log(err, s.Handlers)
+		t.Fail()
+	}
+}
+
+type middleware struct {
+	name     string
+	priority int
+}
+
+func (m *middleware) Handle() string {
+	return m.name
+}
+
+func (m *middleware) Priority() int {
+	return m.priority
+}
+
+func TestAutoInjectsSliceSortedByPriority(t *testing.T) {
+	type simpleStruct struct {
+		Handlers []testHandler `summer:",auto"`
+	}
+
+	container := NewContainer()
+	container.Add(&middleware{name: "auth", priority: 20}, "")
+	container.Add(namedHandler("unprioritized"), "")
+	container.Add(&middleware{name: "logging", priority: -10}, "")
+	container.Add(&middleware{name: "recovery", priority: 10}, "")
+	s := new(simpleStruct)
+	err := container.InjectInto(s)
+
+	var names []string
+	for _, handler := range s.Handlers {
+		names = append(names, handler.Handle())
+	}
+	if err != nil || !reflect.DeepEqual(names, []string{"logging", "unprioritized", "recovery", "auth"}) {
+		t.Log(err, names)
+		t.Fail()
+	}
+}
+
+func TestAutoInjectsRegisteredSliceOverElements(t *testing.T) {
+	type simpleStruct struct {
+		Names []string `summer:",auto"`
+	}
+
+	container := NewContainer()
+	container.Add("a", "")
+	container.Add([]string{"b", "c"}, "")
+	s := new(simpleStruct)
+	err := container.InjectInto(s)
+
+	if err != nil || !reflect.DeepEqual(s.Names, []string{"b", "c"}) {
+		t.Log(err, s.Names)
+		t.Fail()
+	}
+}
+
+func TestDuplicateTypePolicies(t *testing.T) {
+	type simpleStruct struct {
+		Handler namedHandler `summer:",auto"`
+	}
+
+	for _, test := range []struct {
+		policy   DuplicateTypePolicy
+		expected namedHandler
+		fails    bool
+	}{
+		{DuplicateLastWins, "b", false},
+		{DuplicateFirstWins, "a", false},
+		{DuplicateError, "a", true},
+	} {
+		container := NewContainer()
+		container.OnDuplicateType = test.policy
+		firstErr := container.AddE(namedHandler("a"), "A")
+		secondErr := container.AddE(namedHandler("b"), "B")
+		s := new(simpleStruct)
+		err := container.InjectInto(s)
+
+		if firstErr != nil || (secondErr != nil) != test.fails || err != nil || s.Handler != test.expected {
+			t.Log(test.policy, firstErr, secondErr, err)
+			t.Fail()
+		}
+	}
+}
+
+func TestDuplicateErrorAllowsSameDependencyTwice(t *testing.T) {
+	container := NewContainer()
+	container.OnDuplicateType = DuplicateError
+	cache := new(redisCache)
+	container.Add(cache, "A")
+
+	if err := container.AddE(cache, "B"); err != nil {
+		t.Log(err)
+		t.Fail()
+	}
+}
+
+func TestIfNilKeepsPresetField(t *testing.T) {
+	type simpleStruct struct {
+		Preset  *hookTestingStruct `summer:"DB,,ifNil"`
+		Missing *hookTestingStruct `summer:"DB,ifNil"`
+		Always  *hookTestingStruct `summer:"DB"`
+	}
+
+	container := NewContainer()
+	registered := &hookTestingStruct{}
+	preset := &hookTestingStruct{}
+	container.Add(registered, "DB")
+	s := &simpleStruct{Preset: preset, Always: preset}
+	err := container.InjectInto(s)
+
+	if err != nil || s.Preset != preset || s.Missing != registered || s.Always != registered {
+		t.Log(err)
+		t.Fail()
+	}
+}
+
+func TestGetAllOfType(t *testing.T) {
+	container := NewContainer()
+	wrapped := &wrappedHandler{inner: namedHandler("c")}
+	container.Add(upperHandler("a"), "")
+	container.Add("not a handler", "")
+	container.Add(namedHandler("b"), "B")
+	container.Add(wrapped, "")
+	all := container.GetAllOfType(reflect.TypeOf((*testHandler)(nil)).Elem())
+
+	expected := []interface{}{upperHandler("a"), namedHandler("b"), wrapped}
+	if !reflect.DeepEqual(all, expected) {
+		t.Log(all)
+		t.Fail()
+	}
+}
+
+func TestSplitTag(t *testing.T) {
+	for rawTag, expected := range map[string][]string{
+		"name,auto":               {"name", "auto"},
+		" name , auto ":           {"name", "auto"},
+		"'My,Weird,Name',auto":    {"My,Weird,Name", "auto"},
+		"' spaced ' ,auto":        {" spaced ", "auto"},
+		"'It''s',,ifNil":          {"It's", "", "ifNil"},
+		"type='map[string, int]'": {"type=map[string, int]"},
+		",auto":                   {"", "auto"},
+	} {
+		if components := splitTag(rawTag); !reflect.DeepEqual(components, expected) {
+			t.Logf("%q: %q", rawTag, components)
+			t.Fail()
+		}
+	}
+}
+
+func TestInjectsQuotedAndSpacedNames(t *testing.T) {
+	type simpleStruct struct {
+		Quoted string `summer:"'My,Weird,Name'"`
+		Spaced string `summer:"  spaced name  , ifNil"`
+	}
+
+	container := NewContainer()
+	container.Add("quoted", "My,Weird,Name")
+	container.Add("spaced", "spaced name")
+	s := new(simpleStruct)
+	err := container.InjectInto(s)
+
+	if err != nil || s.Quoted != "quoted" || s.Spaced != "spaced" {
+		t.Log(err)
+		t.Fail()
+	}
+}
+
+func TestStrictAutoRejectsShadowedType(t *testing.T) {
+	type autoStruct struct {
+		Handler namedHandler `summer:",auto"`
+	}
+	type qualifiedStruct struct {
+		Handler namedHandler `summer:"A,auto"`
+	}
+
+	container := NewContainer()
+	container.StrictAuto = true
+	container.Add(namedHandler("a"), "A")
+	container.Add(namedHandler("b"), "B")
+	autoErr := container.InjectInto(new(autoStruct))
+	qualified := new(qualifiedStruct)
+	qualifiedErr := container.InjectInto(qualified)
+
+	if autoErr == nil || !strings.Contains(autoErr.Error(), "Ambiguous") {
+		t.Log(autoErr)
+		t.Fail()
+	}
+	if qualifiedErr != nil || qualified.Handler != namedHandler("a") {
+		t.Log(qualifiedErr)
+		t.Fail()
+	}
+}
+
+type boxedService interface {
+	Name() string
+}
+
+type boxedServiceImpl struct {
+	Prefix string `summer:"prefix"`
+}
+
+func (s *boxedServiceImpl) Name() string {
+	return s.Prefix + "service"
+}
+
+func TestPerformInjectionsInjectsInterfaceBoxedStruct(t *testing.T) {
+	var service boxedService = &boxedServiceImpl{}
+
+	container := NewContainer()
+	container.Add("my ", "prefix")
+	container.Add(service, "service")
+	err := container.PerformInjections()
+
+	if err != nil || service.Name() != "my service" {
+		t.Log(err)
+		t.Fail()
+	}
+}
+
+func TestInjectsByFieldName(t *testing.T) {
+	type simpleStruct struct {
+		Logger string `summer:","`
+		Other  string `summer:" , "`
+	}
+
+	container := NewContainer()
+	container.Add("logger", "Logger")
+	container.Add("other", "Other")
+	container.Add("by type", "")
+	s := new(simpleStruct)
+	err := container.InjectInto(s)
+
+	if err != nil || s.Logger != "logger" || s.Other != "other" {
+		t.Log(err)
+		t.Fail()
+	}
+}
+
+func TestThrowsErrorForMissingFieldName(t *testing.T) {
+	type simpleStruct struct {
+		Logger string `summer:","`
+	}
+
+	container := NewContainer()
+	container.Add("by type", "")
+	err := container.InjectInto(new(simpleStruct))
+
+	if !errors.Is(err, ErrMissingDependency) || !strings.Contains(err.Error(), "dependency Logger") {
+		t.Log(err)
+		t.Fail()
+	}
+}
+
+func TestAutoInjectsTypeKeyedMap(t *testing.T) {
+	type simpleStruct struct {
+		Handlers map[reflect.Type]testHandler `summer:",auto"`
+	}
+
+	container := NewContainer()
+	wrapped := &wrappedHandler{inner: namedHandler("b")}
+	container.Add(namedHandler("a"), "A")
+	container.Add(wrapped, "")
+	container.Add("not a handler", "")
+	s := new(simpleStruct)
+	err := container.InjectInto(s)
+
+	if err != nil || len(s.Handlers) != 2 ||
+		s.Handlers[reflect.TypeOf(namedHandler(""))] != namedHandler("a") ||
+		s.Handlers[reflect.TypeOf(wrapped)] != wrapped {
+		t.Log(err, s.Handlers)
+		t.Fail()
+	}
+}
+
+func TestThrowsErrorForUnsupportedMapKey(t *testing.T) {
+	type simpleStruct struct {
+		Handlers map[int]testHandler `summer:",auto"`
+	}
+
+	container := NewContainer()
+	err := container.InjectInto(new(simpleStruct))
+
+	if err == nil || !strings.Contains(err.Error(), "keyed by string or reflect.Type") {
+		t.Log(err)
+		t.Fail()
+	}
+}
+
+func TestAddWithNames(t *testing.T) {
+	container := NewContainer()
+	cache := new(hookTestingStruct)
+	container.AddWithNames(cache, "cache", "store", "backend")
+
+	for _, name := range []string{"cache", "store", "backend"} {
+		if dependency, ok := container.Get(name); !ok || dependency != cache {
+			t.Log(name)
+			t.Fail()
+		}
+	}
+	if container.Len() != 1 || container.TypeCounts()[reflect.TypeOf(cache)] != 1 {
+		t.Fail()
+	}
+}
+
+func TestAddWithNamesChecksEveryNameFirst(t *testing.T) {
+	container := NewContainer()
+	container.StrictNames = true
+	container.Add("taken", "store")
+
+	defer func() {
+		if _, ok := container.Get("cache"); recover() == nil || ok {
+			t.Fail()
+		}
+	}()
+	container.AddWithNames(new(hookTestingStruct), "cache", "store")
+}
+
 func ExampleContainer_PerformInjections() {
 	// All structs are set up similar to the example for InjectInto.
 	type ServiceOne struct {
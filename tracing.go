@@ -0,0 +1,48 @@
+package summer
+
+// Receives spans describing the work done by a container, allowing
+// injection to show up in an existing tracing system.
+type Tracer interface {
+	StartSpan(name string) Span
+}
+
+// A single traced operation, ended once the operation completes.
+type Span interface {
+	SetAttribute(key string, value interface{})
+	End()
+}
+
+// Span names and attributes reported to a Tracer
+const (
+	spanInject         = "summer.inject"
+	spanHook           = "summer.hook"
+	attributeType      = "summer.target.type"
+	attributeNumFields = "summer.target.fields"
+)
+
+// Traces every injection and post injection hook performed by the container
+// with the given tracer. A nil tracer disables tracing.
+func (c *Container) SetTracer(tracer Tracer) {
+	c.tracer = tracer
+}
+
+// Starts a span describing an operation on target, returning nil when
+// tracing is disabled
+func (c *Container) startSpan(name string, target interface{}) Span {
+	if c.tracer == nil {
+		return nil
+	}
+
+	targetType := getDereferencedType(target)
+	span := c.tracer.StartSpan(name)
+	span.SetAttribute(attributeType, targetType.String())
+	span.SetAttribute(attributeNumFields, len(taggedFieldIndices(targetType)))
+
+	return span
+}
+
+func endSpan(span Span) {
+	if span != nil {
+		span.End()
+	}
+}
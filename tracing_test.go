@@ -0,0 +1,56 @@
+package summer
+
+import "testing"
+
+type fakeSpan struct {
+	name       string
+	attributes map[string]interface{}
+	ended      bool
+}
+
+func (s *fakeSpan) SetAttribute(key string, value interface{}) {
+	s.attributes[key] = value
+}
+
+func (s *fakeSpan) End() {
+	s.ended = true
+}
+
+type fakeTracer struct {
+	spans []*fakeSpan
+}
+
+func (t *fakeTracer) StartSpan(name string) Span {
+	span := &fakeSpan{name: name, attributes: make(map[string]interface{})}
+	t.spans = append(t.spans, span)
+	return span
+}
+
+type tracedStruct struct {
+	hookTestingStruct
+	Name string `summer:"Name"`
+}
+
+func TestTracesInjectionAndHook(t *testing.T) {
+	tracer := new(fakeTracer)
+
+	container := NewContainer()
+	container.SetTracer(tracer)
+	container.Add("value", "Name")
+	err := container.InjectInto(new(tracedStruct))
+
+	if err != nil || len(tracer.spans) != 2 {
+		t.Log(err)
+		t.FailNow()
+	}
+
+	inject, hook := tracer.spans[0], tracer.spans[1]
+	if inject.name != spanInject || hook.name != spanHook || !inject.ended || !hook.ended {
+		t.Fail()
+	}
+	if inject.attributes[attributeType] != "summer.tracedStruct" ||
+		inject.attributes[attributeNumFields] != 1 {
+		t.Log(inject.attributes)
+		t.Fail()
+	}
+}
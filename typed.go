@@ -0,0 +1,79 @@
+package summer
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// Adds impl to the container like Container.Add, additionally recording it
+// as the implementation of the interface I. Fields of type I can then be
+// auto injected with impl, which otherwise only happens for fields of
+// impl's concrete type. The last implementation added as an interface is
+// the one injected.
+//
+//	summer.AddAs[Mailer](container, &smtpMailer{}, "")
+func AddAs[I any](c *Container, impl I, name string) {
+	c.Add(impl, name)
+	c.dependenciesByInterface[reflect.TypeOf((*I)(nil)).Elem()] = impl
+}
+
+// Adds value to the container under the static type T, exactly as AddTyped
+// would with T's reflect.Type. This keeps the type intended by the caller
+// when value is held in an interface variable, whose dynamic type would
+// otherwise be used:
+//
+//	var mailer Mailer = &smtpMailer{}
+//	summer.Provide(container, mailer, "") // Registered as Mailer
+func Provide[T any](c *Container, value T, name string) {
+	c.AddTyped(value, reflect.TypeOf((*T)(nil)).Elem(), name)
+}
+
+// Adds target to the container under the type t, rather than its own type,
+// so it can be auto injected into fields of type t. Unlike Add, target may
+// be nil, which registers the zero value of t: a field of type t is then
+// deliberately left nil instead of failing to inject.
+//
+// AddTyped panics if target isn't assignable to t, or in the situations
+// where Add would.
+func (c *Container) AddTyped(target interface{}, t reflect.Type, name string) {
+	if err := c.checkFrozen(); err != nil {
+		panic(err)
+	}
+	if target == nil {
+		target = reflect.Zero(t).Interface()
+	} else if !reflect.TypeOf(target).AssignableTo(t) {
+		panic(fmt.Sprintf("Summer: Cannot add %T as %s", target, t))
+	}
+
+	if target != nil {
+		c.Add(target, name)
+	} else if name != "" {
+		// A nil interface has no type of its own to be registered under
+		if err := c.checkName(name); err != nil {
+			panic(err)
+		}
+		c.mutex.Lock()
+		c.dependenciesByName[name] = nil
+		c.mutex.Unlock()
+	}
+	c.mutex.Lock()
+	c.dependenciesByType[t] = target
+	c.mutex.Unlock()
+}
+
+// Adds the value held by v, for callers working with reflection. The value is
+// registered under v's own type, exactly as with AddTyped, so a value read
+// from an interface field is registered under the interface. v doesn't need
+// to be addressable.
+//
+// AddReflectValue panics if v is the zero Value or was obtained through an
+// unexported field, or in the situations where Add would.
+func (c *Container) AddReflectValue(v reflect.Value, name string) {
+	if !v.IsValid() {
+		panic("Summer: Cannot add an invalid reflect.Value")
+	} else if !v.CanInterface() {
+		panic(fmt.Sprintf("Summer: Cannot add a %s obtained through an unexported field", v.Type()))
+	}
+
+	c.AddTyped(v.Interface(), v.Type(), name)
+}
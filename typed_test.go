@@ -0,0 +1,151 @@
+package summer
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestAddAsInjectsIntoInterfaceField(t *testing.T) {
+	type simpleStruct struct {
+		Handler testHandler `summer:",auto"`
+	}
+
+	container := NewContainer()
+	AddAs[testHandler](container, namedHandler("a"), "")
+	s := new(simpleStruct)
+	err := container.InjectInto(s)
+
+	if err != nil || s.Handler != namedHandler("a") {
+		t.Log(err)
+		t.Fail()
+	}
+}
+
+func TestAddAsKeepsConcreteAndNamedRegistrations(t *testing.T) {
+	type simpleStruct struct {
+		Concrete namedHandler `summer:",auto"`
+		Named    testHandler  `summer:"handler"`
+	}
+
+	container := NewContainer()
+	AddAs[testHandler](container, namedHandler("a"), "handler")
+	s := new(simpleStruct)
+	err := container.InjectInto(s)
+
+	if err != nil || s.Concrete != namedHandler("a") || s.Named != namedHandler("a") {
+		t.Log(err)
+		t.Fail()
+	}
+}
+
+func TestReplaceRemovesInterfaceRegistration(t *testing.T) {
+	type simpleStruct struct {
+		Handler testHandler `summer:",auto"`
+	}
+
+	container := NewContainer()
+	AddAs[testHandler](container, namedHandler("a"), "handler")
+	container.Replace("not a handler", "handler")
+	err := container.InjectInto(new(simpleStruct))
+
+	if err == nil {
+		t.Fail()
+	}
+}
+
+func TestAddTypedInjectsTypedNil(t *testing.T) {
+	type simpleStruct struct {
+		Cache   *memoryCache `summer:",auto"`
+		Handler testHandler  `summer:",auto"`
+		Named   testHandler  `summer:"handler"`
+	}
+
+	container := NewContainer()
+	container.AddTyped(nil, reflect.TypeOf((*memoryCache)(nil)), "")
+	container.AddTyped(nil, reflect.TypeOf((*testHandler)(nil)).Elem(), "handler")
+	s := &simpleStruct{Cache: new(memoryCache), Handler: namedHandler("a"), Named: namedHandler("b")}
+	err := container.InjectInto(s)
+
+	if err != nil || s.Cache != nil || s.Handler != nil || s.Named != nil {
+		t.Log(err)
+		t.Fail()
+	}
+}
+
+func TestPerformInjectionsSkipsTypedNilPointers(t *testing.T) {
+	type cache struct {
+		Name string `summer:"name"`
+	}
+
+	for _, logger := range []Logger{nil, new(capturingLogger)} {
+		container := NewContainer()
+		container.SetLogger(logger)
+		container.Add("value", "name")
+		container.AddTyped(nil, reflect.TypeOf((*cache)(nil)), "")
+		err := container.PerformInjections()
+
+		if err != nil {
+			t.Log(err)
+			t.Fail()
+		}
+	}
+}
+
+func TestAddTypedRegistersUnderInterface(t *testing.T) {
+	type simpleStruct struct {
+		Handler testHandler `summer:",auto"`
+	}
+
+	container := NewContainer()
+	container.AddTyped(namedHandler("a"), reflect.TypeOf((*testHandler)(nil)).Elem(), "")
+	s := new(simpleStruct)
+	err := container.InjectInto(s)
+
+	if err != nil || s.Handler != namedHandler("a") {
+		t.Log(err)
+		t.Fail()
+	}
+}
+
+func TestAddReflectValue(t *testing.T) {
+	type service struct {
+		Name string
+	}
+	type simpleStruct struct {
+		Service *service    `summer:",auto"`
+		Named   *service    `summer:"service"`
+		Handler testHandler `summer:",auto"`
+	}
+	holder := struct{ Handler testHandler }{Handler: namedHandler("a")}
+	svc := &service{Name: "reflected"}
+
+	container := NewContainer()
+	container.AddReflectValue(reflect.ValueOf(svc), "service")
+	container.AddReflectValue(reflect.ValueOf(holder).Field(0), "")
+	s := new(simpleStruct)
+	err := container.InjectInto(s)
+
+	if err != nil || s.Service != svc || s.Named != svc || s.Handler != namedHandler("a") {
+		t.Log(err, s)
+		t.Fail()
+	}
+}
+
+func TestProvideRegistersStaticType(t *testing.T) {
+	type simpleStruct struct {
+		Handler testHandler `summer:",auto"`
+	}
+	var handler testHandler = namedHandler("a")
+
+	container := NewContainer()
+	Provide(container, handler, "handler")
+	s := new(simpleStruct)
+	err := container.InjectInto(s)
+	named, _ := container.Get("handler")
+
+	if err != nil || s.Handler != handler || named != handler ||
+		container.dependenciesByType[reflect.TypeOf((*testHandler)(nil)).Elem()] != handler {
+		t.Log(err)
+		t.Fail()
+	}
+}
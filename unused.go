@@ -0,0 +1,36 @@
+package summer
+
+import "reflect"
+
+// Records that the dependency was injected into a field
+func (c *Container) markUsed(dependency interface{}) {
+	if reflect.ValueOf(dependency).Comparable() {
+		c.used.Add(dependency)
+	}
+}
+
+// Returns the dependencies that haven't been injected into any field since
+// PerformInjections last began, to help find wiring that's no longer needed.
+// Each is described by the names it was added under, or by its type if it
+// was added without a name. Structs that only receive injections, such as
+// the top level of an application, are included too, since nothing
+// depends on them.
+func (c *Container) UnusedDependencies() []string {
+	dependencies := c.activeDependencies()
+	namesOf := c.namesByIndex(dependencies)
+
+	unused := []string{}
+	for index, dependency := range dependencies {
+		if reflect.ValueOf(dependency).Comparable() && c.used.Contains(dependency) {
+			continue
+		}
+
+		if names := namesOf[index]; len(names) > 0 {
+			unused = append(unused, names...)
+		} else {
+			unused = append(unused, "type "+reflect.TypeOf(dependency).String())
+		}
+	}
+
+	return unused
+}
@@ -0,0 +1,39 @@
+package summer
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestUnusedDependencies(t *testing.T) {
+	type simpleStruct struct {
+		Used string `summer:"used"`
+	}
+
+	container := NewContainer()
+	container.Add("value", "used")
+	container.Add("other", "unused")
+	container.Add(3, "")
+	container.Add(new(simpleStruct), "app")
+	err := container.PerformInjections()
+
+	expected := []string{"unused", "type int", "app"}
+	if unused := container.UnusedDependencies(); err != nil || !reflect.DeepEqual(unused, expected) {
+		t.Log(err, unused)
+		t.Fail()
+	}
+}
+
+func TestUnusedDependenciesAfterReplace(t *testing.T) {
+	container := NewContainer()
+	container.Add("first", "a")
+	container.Add("second", "b")
+	container.Add("third", "c")
+	container.Replace("fourth", "a")
+
+	expected := []string{"b", "c", "a"}
+	if unused := container.UnusedDependencies(); !reflect.DeepEqual(unused, expected) {
+		t.Log(unused)
+		t.Fail()
+	}
+}
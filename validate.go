@@ -0,0 +1,41 @@
+package summer
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// Checks the options of every summer tag on target's fields, returning an
+// error listing any that aren't recognised, such as a misspelled auto.
+// Misspelled options are otherwise silently ignored.
+func (c *Container) ValidateTags(target interface{}) error {
+	if target == nil {
+		return fmt.Errorf("Summer: Can only validate the tags of a pointer to a struct, got nil: %w",
+			ErrNotAStruct)
+	}
+	targetType := getDereferencedType(target)
+	if targetType.Kind() != reflect.Struct {
+		return fmt.Errorf("Summer: Can only validate the tags of a pointer to a struct, got %T: %w",
+			target, ErrNotAStruct)
+	}
+
+	var problems []string
+	for i := 0; i < targetType.NumField(); i++ {
+		field := targetType.Field(i)
+		tag := parseFieldTag(field.Tag.Get(summerTag))
+		if tag == nil || len(tag.unknown) == 0 {
+			continue
+		}
+		problems = append(problems,
+			fmt.Sprintf("%s (%s)", field.Name, strings.Join(tag.unknown, ", ")))
+	}
+
+	if len(problems) > 0 {
+		return errors.New(fmt.Sprintf("Summer: Unrecognised tag options in %s: %s",
+			targetType, strings.Join(problems, "; ")))
+	}
+
+	return nil
+}
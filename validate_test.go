@@ -0,0 +1,74 @@
+package summer
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestValidateTagsFlagsMisspelledOption(t *testing.T) {
+	type simpleStruct struct {
+		Handler *testHandler `summer:",auot"`
+	}
+
+	container := NewContainer()
+	err := container.ValidateTags(new(simpleStruct))
+
+	if err == nil || !strings.Contains(err.Error(), "Handler (auot)") {
+		t.Log(err)
+		t.Fail()
+	}
+}
+
+func TestValidateTagsAcceptsKnownOptions(t *testing.T) {
+	type simpleStruct struct {
+		Handler *testHandler `summer:"handler,auto,orNew"`
+		Named   string       `summer:"name"`
+		Self    interface{}  `summer:",self"`
+	}
+
+	container := NewContainer()
+	err := container.ValidateTags(new(simpleStruct))
+
+	if err != nil {
+		t.Log(err)
+		t.Fail()
+	}
+}
+
+func TestStrictTagsRejectsInvalidTagsOnAdd(t *testing.T) {
+	type simpleStruct struct {
+		Handler *testHandler `summer:",auot"`
+	}
+
+	container := NewContainer()
+	container.StrictTags = true
+	err := container.AddE(new(simpleStruct), "simple")
+
+	if err == nil || container.Len() != 0 {
+		t.Log(err)
+		t.Fail()
+	}
+}
+
+func TestValidateTagsIgnoresEmptyOptions(t *testing.T) {
+	type simpleStruct struct {
+		Handler testHandler `summer:"handler,,ifNil"`
+	}
+
+	container := NewContainer()
+	if err := container.ValidateTags(new(simpleStruct)); err != nil {
+		t.Log(err)
+		t.Fail()
+	}
+}
+
+func TestValidateTagsRejectsNil(t *testing.T) {
+	container := NewContainer()
+	err := container.ValidateTags(nil)
+
+	if !errors.Is(err, ErrNotAStruct) {
+		t.Log(err)
+		t.Fail()
+	}
+}